@@ -1,6 +1,9 @@
 package address
 
 import (
+	"fmt"
+	"regexp"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -9,6 +12,13 @@ const (
 	// Bech32ChainPrefix defines the prefix of this chain
 	Bech32ChainPrefix = "i"
 
+	// FlagBech32PrefixRoot defines the flag and the app config key of the bech32 prefix root,
+	// which replaces Bech32ChainPrefix in all the bech32 prefixes, e.g. for private networks
+	FlagBech32PrefixRoot = "bech32-prefix"
+
+	// EnvBech32PrefixRoot defines the env var of the bech32 prefix root
+	EnvBech32PrefixRoot = "IRIS_BECH32_PREFIX"
+
 	// PrefixAcc is the prefix for account
 	PrefixAcc = "a"
 
@@ -38,10 +48,51 @@ const (
 	Bech32PrefixConsPub = Bech32ChainPrefix + PrefixConsensus + PrefixPublic
 )
 
+var (
+	bech32PrefixRootRegexp = regexp.MustCompile(`^[a-z][a-z0-9]{0,15}$`)
+
+	// bech32PrefixRoot is the prefix root the bech32 prefixes are currently derived from
+	bech32PrefixRoot = Bech32ChainPrefix
+)
+
+// ConfigureBech32Prefix configures the default bech32 prefixes and seals the config
 func ConfigureBech32Prefix() {
+	if err := ConfigureBech32PrefixRoot(Bech32ChainPrefix); err != nil {
+		panic(err)
+	}
+	sdk.GetConfig().Seal()
+}
+
+// ConfigureBech32PrefixRoot configures the bech32 prefixes derived from the given prefix root,
+// e.g. "i" for iaa/iva/ica. The config is not sealed so that the prefix root can be
+// overridden until the app config is read, and an error is returned if it is already sealed.
+func ConfigureBech32PrefixRoot(root string) (err error) {
+	if err := ValidateBech32PrefixRoot(root); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to set the bech32 prefix root %q: %v", root, r)
+		}
+	}()
 	config := sdk.GetConfig()
-	config.SetBech32PrefixForAccount(Bech32PrefixAccAddr, Bech32PrefixAccPub)
-	config.SetBech32PrefixForValidator(Bech32PrefixValAddr, Bech32PrefixValPub)
-	config.SetBech32PrefixForConsensusNode(Bech32PrefixConsAddr, Bech32PrefixConsPub)
-	config.Seal()
+	config.SetBech32PrefixForAccount(root+PrefixAcc+PrefixAddress, root+PrefixAcc+PrefixPublic)
+	config.SetBech32PrefixForValidator(root+PrefixValidator+PrefixAddress, root+PrefixValidator+PrefixPublic)
+	config.SetBech32PrefixForConsensusNode(root+PrefixConsensus+PrefixAddress, root+PrefixConsensus+PrefixPublic)
+	bech32PrefixRoot = root
+	return nil
+}
+
+// GetBech32PrefixRoot returns the prefix root the bech32 prefixes are currently derived from
+func GetBech32PrefixRoot() string {
+	return bech32PrefixRoot
+}
+
+// ValidateBech32PrefixRoot returns an error if the bech32 prefix root is invalid
+func ValidateBech32PrefixRoot(root string) error {
+	if !bech32PrefixRootRegexp.MatchString(root) {
+		return fmt.Errorf("invalid bech32 prefix root %q, must be lowercase alphanumeric starting with a letter and at most 16 characters", root)
+	}
+	return nil
 }
@@ -0,0 +1,171 @@
+package ante
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	authante "github.com/cosmos/cosmos-sdk/x/auth/ante"
+	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
+	"github.com/cosmos/cosmos-sdk/x/auth/signing"
+	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
+)
+
+// Names of the base ante decorators, which can be used as the anchors of the registered decorators
+const (
+	SetUpContext           = "set-up-context"
+	RejectExtensionOptions = "reject-extension-options"
+	MempoolFee             = "mempool-fee"
+	ValidateBasic          = "validate-basic"
+	TxTimeoutHeight        = "tx-timeout-height"
+	ValidateMemo           = "validate-memo"
+	ConsumeGasForTxSize    = "consume-gas-for-tx-size"
+	RejectFeeGranter       = "reject-fee-granter"
+	SetPubKey              = "set-pubkey"
+	ValidateSigCount       = "validate-sig-count"
+	DeductFee              = "deduct-fee"
+	SigGasConsume          = "sig-gas-consume"
+	SigVerification        = "sig-verification"
+	IncrementSequence      = "increment-sequence"
+)
+
+// namedAnteDecorator is an ante decorator in the chain along with the anchor it is registered after or before
+type namedAnteDecorator struct {
+	name      string
+	after     string
+	before    string
+	decorator sdk.AnteDecorator
+}
+
+// Registry assembles the ante decorator chain from the base decorators and
+// the decorators registered by the modules relative to the named anchors
+type Registry struct {
+	chain []namedAnteDecorator
+}
+
+// Module is implemented by the app modules contributing ante decorators to the chain
+type Module interface {
+	RegisterAnteDecorators(r *Registry)
+}
+
+// NewRegistry returns a Registry containing the base decorators which check
+// and increment sequence numbers, check signatures & account numbers, and deduct fees
+// from the first signer.
+func NewRegistry(
+	ak authkeeper.AccountKeeper,
+	bk bankkeeper.Keeper,
+	sigGasConsumer authante.SignatureVerificationGasConsumer,
+	signModeHandler signing.SignModeHandler,
+) *Registry {
+	return &Registry{
+		chain: []namedAnteDecorator{
+			{name: SetUpContext, decorator: authante.NewSetUpContextDecorator()}, // outermost AnteDecorator. SetUpContext must be called first
+			{name: RejectExtensionOptions, decorator: authante.NewRejectExtensionOptionsDecorator()},
+			{name: MempoolFee, decorator: authante.NewMempoolFeeDecorator()},
+			{name: ValidateBasic, decorator: authante.NewValidateBasicDecorator()},
+			{name: TxTimeoutHeight, decorator: authante.TxTimeoutHeightDecorator{}},
+			{name: ValidateMemo, decorator: authante.NewValidateMemoDecorator(ak)},
+			{name: ConsumeGasForTxSize, decorator: authante.NewConsumeGasForTxSizeDecorator(ak)},
+			{name: RejectFeeGranter, decorator: authante.NewRejectFeeGranterDecorator()},
+			{name: SetPubKey, decorator: authante.NewSetPubKeyDecorator(ak)}, // SetPubKeyDecorator must be called before all signature verification decorators
+			{name: ValidateSigCount, decorator: authante.NewValidateSigCountDecorator(ak)},
+			{name: DeductFee, decorator: authante.NewDeductFeeDecorator(ak, bk)},
+			{name: SigGasConsume, decorator: authante.NewSigGasConsumeDecorator(ak, sigGasConsumer)},
+			{name: SigVerification, decorator: authante.NewSigVerificationDecorator(ak, signModeHandler)},
+			{name: IncrementSequence, decorator: authante.NewIncrementSequenceDecorator(ak)},
+		},
+	}
+}
+
+// RegisterBefore registers the decorator right before the anchor. The decorators
+// registered before the same anchor run in the order of registration.
+func (r *Registry) RegisterBefore(anchor, name string, decorator sdk.AnteDecorator) *Registry {
+	idx := r.mustIndex(anchor, name)
+	r.insert(idx, namedAnteDecorator{name: name, before: anchor, decorator: decorator})
+	return r
+}
+
+// RegisterAfter registers the decorator after the anchor and all the decorators
+// registered relative to it, directly or not. The decorators registered after
+// the same anchor run in the order of registration.
+func (r *Registry) RegisterAfter(anchor, name string, decorator sdk.AnteDecorator) *Registry {
+	idx := r.mustIndex(anchor, name) + 1
+
+	// skip the block of the descendants of the anchor, which follows it
+	block := map[string]bool{anchor: true}
+	for ; idx < len(r.chain) && block[r.parent(r.chain[idx])]; idx++ {
+		block[r.chain[idx].name] = true
+	}
+	r.insert(idx, namedAnteDecorator{name: name, after: anchor, decorator: decorator})
+	return r
+}
+
+// RegisterModules registers the ante decorators of the modules implementing Module, in the given order
+func (r *Registry) RegisterModules(modules map[string]module.AppModule, order []string) *Registry {
+	for _, name := range order {
+		if m, ok := modules[name].(Module); ok {
+			m.RegisterAnteDecorators(r)
+		}
+	}
+	return r
+}
+
+// Names returns the names of the decorators in the order they run
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.chain))
+	for i, d := range r.chain {
+		names[i] = d.name
+	}
+	return names
+}
+
+// AnteHandler returns the AnteHandler chaining all the decorators
+func (r *Registry) AnteHandler() sdk.AnteHandler {
+	decorators := make([]sdk.AnteDecorator, len(r.chain))
+	for i, d := range r.chain {
+		decorators[i] = d.decorator
+	}
+	return sdk.ChainAnteDecorators(decorators...)
+}
+
+// mustIndex returns the position of the anchor, panicking if the anchor does not
+// exist or the name to be registered is taken
+func (r *Registry) mustIndex(anchor, name string) int {
+	idx := -1
+	for i, d := range r.chain {
+		if d.name == name {
+			panic(fmt.Sprintf("ante decorator %s already registered", name))
+		}
+		if d.name == anchor {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		panic(fmt.Sprintf("anchor %s of ante decorator %s not found", anchor, name))
+	}
+	return idx
+}
+
+// parent returns the decorator the given one is registered after, following the decorators
+// registered before another one to that one. It is empty for the base decorators.
+func (r *Registry) parent(d namedAnteDecorator) string {
+	for d.before != "" {
+		d = r.chain[r.index(d.before)]
+	}
+	return d.after
+}
+
+func (r *Registry) index(name string) int {
+	for i, d := range r.chain {
+		if d.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Registry) insert(idx int, d namedAnteDecorator) {
+	r.chain = append(r.chain, namedAnteDecorator{})
+	copy(r.chain[idx+1:], r.chain[idx:])
+	r.chain[idx] = d
+}
@@ -0,0 +1,48 @@
+package ante
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestRegistry(t *testing.T) {
+	newRegistry := func() *Registry {
+		return &Registry{chain: []namedAnteDecorator{{name: "a"}, {name: "b"}}}
+	}
+	var decorator sdk.AnteDecorator
+
+	r := newRegistry().
+		RegisterAfter("a", "a1", decorator).
+		RegisterAfter("a", "a2", decorator).
+		RegisterAfter("a1", "a1.1", decorator).
+		RegisterBefore("b", "b1", decorator).
+		RegisterBefore("b", "b2", decorator)
+	require.Equal(t, []string{"a", "a1", "a1.1", "a2", "b1", "b2", "b"}, r.Names())
+
+	// the decorators registered after an anchor follow all its descendants
+	r = newRegistry().
+		RegisterAfter("a", "x", decorator).
+		RegisterAfter("x", "y", decorator).
+		RegisterAfter("a", "z", decorator)
+	require.Equal(t, []string{"a", "x", "y", "z", "b"}, r.Names())
+
+	// including the ones registered before a descendant
+	r = newRegistry().
+		RegisterAfter("a", "x", decorator).
+		RegisterBefore("x", "w", decorator).
+		RegisterAfter("w", "w1", decorator).
+		RegisterAfter("a", "z", decorator)
+	require.Equal(t, []string{"a", "w", "w1", "x", "z", "b"}, r.Names())
+
+	// but not the ones registered before the next base decorator
+	r = newRegistry().
+		RegisterBefore("b", "b1", decorator).
+		RegisterAfter("a", "z", decorator)
+	require.Equal(t, []string{"a", "z", "b1", "b"}, r.Names())
+
+	require.Panics(t, func() { newRegistry().RegisterAfter("c", "c1", decorator) }, "unknown anchor")
+	require.Panics(t, func() { newRegistry().RegisterBefore("b", "a", decorator) }, "duplicate name")
+}
@@ -1,48 +0,0 @@
-package app
-
-import (
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/auth/ante"
-	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
-	"github.com/cosmos/cosmos-sdk/x/auth/signing"
-	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
-
-	oraclekeeper "github.com/irisnet/irismod/modules/oracle/keeper"
-	tokenkeeper "github.com/irisnet/irismod/modules/token/keeper"
-
-	guardiankeeper "github.com/irisnet/irishub/modules/guardian/keeper"
-)
-
-// NewAnteHandler returns an AnteHandler that checks and increments sequence
-// numbers, checks signatures & account numbers, and deducts fees from the first
-// signer.
-func NewAnteHandler(
-	ak authkeeper.AccountKeeper,
-	bk bankkeeper.Keeper,
-	tk tokenkeeper.Keeper,
-	ok oraclekeeper.Keeper,
-	gk guardiankeeper.Keeper,
-	sigGasConsumer ante.SignatureVerificationGasConsumer,
-	signModeHandler signing.SignModeHandler,
-) sdk.AnteHandler {
-	return sdk.ChainAnteDecorators(
-		ante.NewSetUpContextDecorator(), // outermost AnteDecorator. SetUpContext must be called first
-		ante.NewRejectExtensionOptionsDecorator(),
-		ante.NewMempoolFeeDecorator(),
-		ante.NewValidateBasicDecorator(),
-		ante.TxTimeoutHeightDecorator{},
-		ante.NewValidateMemoDecorator(ak),
-		ante.NewConsumeGasForTxSizeDecorator(ak),
-		ante.NewRejectFeeGranterDecorator(),
-		ante.NewSetPubKeyDecorator(ak), // SetPubKeyDecorator must be called before all signature verification decorators
-		ante.NewValidateSigCountDecorator(ak),
-		ante.NewDeductFeeDecorator(ak, bk),
-		ante.NewSigGasConsumeDecorator(ak, sigGasConsumer),
-		ante.NewSigVerificationDecorator(ak, signModeHandler),
-		NewValidateTokenDecorator(tk),
-		tokenkeeper.NewValidateTokenFeeDecorator(tk, bk),
-		oraclekeeper.NewValidateOracleAuthDecorator(ok, gk),
-		NewValidateServiceDecorator(),
-		ante.NewIncrementSequenceDecorator(ak),
-	)
-}
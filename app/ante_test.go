@@ -0,0 +1,43 @@
+package app
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/simapp"
+
+	"github.com/irisnet/irishub/ante"
+)
+
+func TestAnteDecoratorOrder(t *testing.T) {
+	app := NewIrisApp(log.NewTMLogger(log.NewSyncWriter(os.Stdout)), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, simapp.FlagPeriodValue, MakeEncodingConfig(), EmptyAppOptions{}, interBlockCacheOpt())
+
+	require.Equal(t, []string{
+		ante.SetUpContext,
+		ante.RejectExtensionOptions,
+		ante.MempoolFee,
+		"global-fee",
+		ante.ValidateBasic,
+		ante.TxTimeoutHeight,
+		ante.ValidateMemo,
+		"memo-required",
+		ante.ConsumeGasForTxSize,
+		"surcharge",
+		ante.RejectFeeGranter,
+		ante.SetPubKey,
+		ante.ValidateSigCount,
+		ante.DeductFee,
+		ante.SigGasConsume,
+		ante.SigVerification,
+		"validate-token",
+		"validate-token-fee",
+		"validate-oracle-auth",
+		"validate-service",
+		ante.IncrementSequence,
+	}, app.anteRegistry.Names())
+}
@@ -1,10 +1,14 @@
 package app
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 
+	"github.com/spf13/cast"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
 	tmjson "github.com/tendermint/tendermint/libs/json"
@@ -23,12 +27,12 @@ import (
 	"github.com/cosmos/cosmos-sdk/server/config"
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
 	"github.com/cosmos/cosmos-sdk/simapp"
-	store "github.com/cosmos/cosmos-sdk/store/types"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 	"github.com/cosmos/cosmos-sdk/version"
 	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/cosmos/cosmos-sdk/x/auth/ante"
+	sdkante "github.com/cosmos/cosmos-sdk/x/auth/ante"
 	authrest "github.com/cosmos/cosmos-sdk/x/auth/client/rest"
 	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
 	authsims "github.com/cosmos/cosmos-sdk/x/auth/simulation"
@@ -78,7 +82,6 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/upgrade"
 	upgradeclient "github.com/cosmos/cosmos-sdk/x/upgrade/client"
 	upgradekeeper "github.com/cosmos/cosmos-sdk/x/upgrade/keeper"
-	sdkupgrade "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 
 	"github.com/irisnet/irismod/modules/coinswap"
@@ -107,16 +110,27 @@ import (
 	tokentypes "github.com/irisnet/irismod/modules/token/types"
 
 	"github.com/irisnet/irishub/address"
+	"github.com/irisnet/irishub/ante"
 	irisappparams "github.com/irisnet/irishub/app/params"
 	"github.com/irisnet/irishub/lite"
+	"github.com/irisnet/irishub/migrate"
 	migratehtlc "github.com/irisnet/irishub/migrate/htlc"
 	migrateservice "github.com/irisnet/irishub/migrate/service"
+	"github.com/irisnet/irishub/modules/globalfee"
+	globalfeekeeper "github.com/irisnet/irishub/modules/globalfee/keeper"
+	globalfeetypes "github.com/irisnet/irishub/modules/globalfee/types"
 	"github.com/irisnet/irishub/modules/guardian"
 	guardiankeeper "github.com/irisnet/irishub/modules/guardian/keeper"
 	guardiantypes "github.com/irisnet/irishub/modules/guardian/types"
+	"github.com/irisnet/irishub/modules/memo"
+	memokeeper "github.com/irisnet/irishub/modules/memo/keeper"
+	memotypes "github.com/irisnet/irishub/modules/memo/types"
 	"github.com/irisnet/irishub/modules/mint"
 	mintkeeper "github.com/irisnet/irishub/modules/mint/keeper"
 	minttypes "github.com/irisnet/irishub/modules/mint/types"
+	"github.com/irisnet/irishub/modules/surcharge"
+	surchargekeeper "github.com/irisnet/irishub/modules/surcharge/keeper"
+	surchargetypes "github.com/irisnet/irishub/modules/surcharge/types"
 
 	"github.com/irisnet/irismod/modules/farm"
 	farmkeeper "github.com/irisnet/irismod/modules/farm/keeper"
@@ -165,6 +179,9 @@ var (
 		oracle.AppModuleBasic{},
 		random.AppModuleBasic{},
 		farm.AppModuleBasic{},
+		globalfee.AppModuleBasic{},
+		surcharge.AppModuleBasic{},
+		memo.AppModuleBasic{},
 	)
 
 	// module account permissions
@@ -205,6 +222,9 @@ type IrisApp struct {
 
 	invCheckPeriod uint
 
+	// the chunked genesis directory the chain is initialized from, if any
+	genesisDir string
+
 	// keys to access the substores
 	keys    map[string]*sdk.KVStoreKey
 	tkeys   map[string]*sdk.TransientStoreKey
@@ -242,24 +262,27 @@ type IrisApp struct {
 	randomKeeper   randomkeeper.Keeper
 	farmkeeper     farmkeeper.Keeper
 
+	globalFeeKeeper globalfeekeeper.Keeper
+	surchargeKeeper surchargekeeper.Keeper
+	memoKeeper      memokeeper.Keeper
+
 	// the module manager
 	mm *module.Manager
 
 	// simulation manager
 	sm *module.SimulationManager
+
+	// the ante decorator chain
+	anteRegistry *ante.Registry
+
+	// the registry of the upgrade plans
+	migrations *migrate.Registry
 }
 
 func init() {
-	address.ConfigureBech32Prefix()
-	nativeToken = tokentypes.Token{
-		Symbol:        "iris",
-		Name:          "Irishub staking token",
-		Scale:         6,
-		MinUnit:       "uiris",
-		InitialSupply: 2000000000,
-		MaxSupply:     10000000000,
-		Mintable:      true,
-		Owner:         sdk.AccAddress(crypto.AddressHash([]byte(tokentypes.ModuleName))).String(),
+	// the default prefix root can be overridden by SetBech32PrefixRoot until the config is sealed
+	if err := address.ConfigureBech32PrefixRoot(address.Bech32ChainPrefix); err != nil {
+		panic(err)
 	}
 
 	userHomeDir, err := os.UserHomeDir()
@@ -269,9 +292,32 @@ func init() {
 
 	DefaultNodeHome = filepath.Join(userHomeDir, ".iris")
 
-	owner, err := sdk.AccAddressFromBech32(nativeToken.Owner)
-	if err != nil {
-		panic(err)
+	setNativeToken()
+}
+
+// SetBech32PrefixRoot configures the bech32 prefixes derived from the given prefix root
+// and updates the native token owner accordingly. It must be called before the sdk
+// config is sealed, which NewIrisApp does.
+func SetBech32PrefixRoot(root string) error {
+	if err := address.ConfigureBech32PrefixRoot(root); err != nil {
+		return err
+	}
+	setNativeToken()
+	return nil
+}
+
+// setNativeToken sets the native token, whose owner is encoded with the current bech32 prefix
+func setNativeToken() {
+	owner := sdk.AccAddress(crypto.AddressHash([]byte(tokentypes.ModuleName)))
+	nativeToken = tokentypes.Token{
+		Symbol:        "iris",
+		Name:          "Irishub staking token",
+		Scale:         6,
+		MinUnit:       "uiris",
+		InitialSupply: 2000000000,
+		MaxSupply:     10000000000,
+		Mintable:      true,
+		Owner:         owner.String(),
 	}
 
 	tokentypes.SetNativeToken(
@@ -293,6 +339,9 @@ func NewIrisApp(
 	appOpts servertypes.AppOptions, baseAppOptions ...func(*baseapp.BaseApp),
 ) *IrisApp {
 
+	// the bech32 prefixes can no longer change once the app is created
+	sdk.GetConfig().Seal()
+
 	// TODO: Remove cdc in favor of appCodec once all modules are migrated.
 	appCodec := encodingConfig.Marshaler
 	legacyAmino := encodingConfig.Amino
@@ -310,7 +359,7 @@ func NewIrisApp(
 		evidencetypes.StoreKey, ibctransfertypes.StoreKey, capabilitytypes.StoreKey,
 		guardiantypes.StoreKey, tokentypes.StoreKey, nfttypes.StoreKey, htlctypes.StoreKey, recordtypes.StoreKey,
 		coinswaptypes.StoreKey, servicetypes.StoreKey, oracletypes.StoreKey, randomtypes.StoreKey,
-		farmtypes.StoreKey,
+		farmtypes.StoreKey, memotypes.StoreKey,
 	)
 	tkeys := sdk.NewTransientStoreKeys(paramstypes.TStoreKey)
 	memKeys := sdk.NewMemoryStoreKeys(capabilitytypes.MemStoreKey)
@@ -321,6 +370,7 @@ func NewIrisApp(
 		appCodec:          appCodec,
 		interfaceRegistry: interfaceRegistry,
 		invCheckPeriod:    invCheckPeriod,
+		genesisDir:        cast.ToString(appOpts.Get(FlagGenesisDir)),
 		keys:              keys,
 		tkeys:             tkeys,
 		memKeys:           memKeys,
@@ -469,6 +519,10 @@ func NewIrisApp(
 		authtypes.FeeCollectorName,
 	)
 
+	app.globalFeeKeeper = globalfeekeeper.NewKeeper(app.GetSubspace(globalfeetypes.ModuleName))
+	app.surchargeKeeper = surchargekeeper.NewKeeper(app.GetSubspace(surchargetypes.ModuleName))
+	app.memoKeeper = memokeeper.NewKeeper(appCodec, keys[memotypes.StoreKey])
+
 	/****  Module Options ****/
 	var skipGenesisInvariants = false
 	opt := appOpts.Get(crisis.FlagSkipGenesisInvariants)
@@ -508,6 +562,9 @@ func NewIrisApp(
 		oracle.NewAppModule(appCodec, app.oracleKeeper, app.accountKeeper, app.bankKeeper),
 		random.NewAppModule(appCodec, app.randomKeeper, app.accountKeeper, app.bankKeeper),
 		farm.NewAppModule(appCodec, app.farmkeeper, app.accountKeeper, app.bankKeeper),
+		globalfee.NewAppModule(appCodec, app.globalFeeKeeper),
+		surcharge.NewAppModule(appCodec, app.surchargeKeeper),
+		memo.NewAppModule(appCodec, app.memoKeeper),
 	)
 
 	// During begin block slashing happens after distr.BeginBlocker so that
@@ -535,6 +592,9 @@ func NewIrisApp(
 		ibchost.ModuleName, genutiltypes.ModuleName, evidencetypes.ModuleName, ibctransfertypes.ModuleName,
 		guardiantypes.ModuleName, tokentypes.ModuleName, nfttypes.ModuleName, htlctypes.ModuleName, recordtypes.ModuleName,
 		coinswaptypes.ModuleName, servicetypes.ModuleName, oracletypes.ModuleName, randomtypes.ModuleName, farmtypes.ModuleName,
+		globalfeetypes.ModuleName,
+		surchargetypes.ModuleName,
+		memotypes.ModuleName,
 	)
 
 	app.mm.RegisterInvariants(&app.crisisKeeper)
@@ -580,30 +640,82 @@ func NewIrisApp(
 	// initialize BaseApp
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
-	app.SetAnteHandler(NewAnteHandler(
+	// the modules register their decorators first, followed by the ones of the irismod modules
+	app.anteRegistry = ante.NewRegistry(
 		app.accountKeeper,
 		app.bankKeeper,
-		app.tokenKeeper,
-		app.oracleKeeper,
-		app.guardianKeeper,
-		ante.DefaultSigVerificationGasConsumer,
+		sdkante.DefaultSigVerificationGasConsumer,
 		encodingConfig.TxConfig.SignModeHandler(),
-	))
+	).
+		RegisterModules(app.mm.Modules, app.mm.OrderInitGenesis).
+		RegisterAfter(ante.SigVerification, "validate-token", NewValidateTokenDecorator(app.tokenKeeper, DefaultTokenRules())).
+		RegisterAfter(ante.SigVerification, "validate-token-fee", tokenkeeper.NewValidateTokenFeeDecorator(app.tokenKeeper, app.bankKeeper)).
+		RegisterAfter(ante.SigVerification, "validate-oracle-auth", oraclekeeper.NewValidateOracleAuthDecorator(app.oracleKeeper, app.guardianKeeper)).
+		RegisterAfter(ante.SigVerification, "validate-service", NewValidateServiceDecorator())
+	app.SetAnteHandler(app.anteRegistry.AnteHandler())
 	app.SetEndBlocker(app.EndBlocker)
 	// Set software upgrade execution logic
-	app.RegisterUpgradePlan(
-		"v1.1", &store.StoreUpgrades{},
-		func(ctx sdk.Context, plan sdkupgrade.Plan) {
-			// migrate htlc
-			if err := migratehtlc.Migrate(ctx, appCodec, app.htlcKeeper, app.bankKeeper, keys[htlctypes.StoreKey]); err != nil {
-				panic(err)
-			}
-			// migrate service
-			if err := migrateservice.Migrate(ctx, app.serviceKeeper, app.bankKeeper); err != nil {
-				panic(err)
-			}
-		},
-	)
+	htlcReportFile := cast.ToString(appOpts.Get(migratehtlc.FlagReportFile))
+
+	app.migrations = migrate.NewRegistry().
+		Register(migrate.Plan{
+			Name: "v1.1",
+			Steps: []migrate.Step{
+				{
+					Module: htlctypes.ModuleName,
+					Name:   "re-key htlcs by id and refund expired htlcs",
+					Migrate: func(ctx sdk.Context, plan upgradetypes.Plan) error {
+						params, err := migratehtlc.PlanHTLTParams(appCodec, plan.Info)
+						if err != nil {
+							return err
+						}
+						report, err := migratehtlc.MigrateWithReport(ctx, appCodec, app.htlcKeeper, app.bankKeeper, keys[htlctypes.StoreKey], params)
+						if err != nil {
+							return err
+						}
+						if htlcReportFile != "" {
+							// the report is informational, failing to write it does not fail the upgrade
+							if err := report.WriteFile(appCodec, htlcReportFile); err != nil {
+								ctx.Logger().Error("failed to write the htlc migration report", "file", htlcReportFile, "err", err)
+							}
+						}
+						return nil
+					},
+				},
+				{
+					Module: servicetypes.ModuleName,
+					Name:   "move service tax account balances to fee collector",
+					Migrate: func(ctx sdk.Context, _ upgradetypes.Plan) error {
+						return migrateservice.Migrate(ctx, app.serviceKeeper, app.bankKeeper)
+					},
+				},
+			},
+		}).
+		Register(migrate.Plan{
+			Name: "v1.2",
+			StoreUpgrades: storetypes.StoreUpgrades{
+				Added: []string{memotypes.StoreKey},
+			},
+			Steps: []migrate.Step{
+				{
+					Module: globalfeetypes.ModuleName,
+					Name:   "initialize params",
+					Migrate: func(ctx sdk.Context, _ upgradetypes.Plan) error {
+						app.globalFeeKeeper.SetParamSet(ctx, globalfeetypes.DefaultParams())
+						return nil
+					},
+				},
+				{
+					Module: surchargetypes.ModuleName,
+					Name:   "initialize params",
+					Migrate: func(ctx sdk.Context, _ upgradetypes.Plan) error {
+						app.surchargeKeeper.SetParamSet(ctx, surchargetypes.DefaultParams())
+						return nil
+					},
+				},
+			},
+		})
+	app.RegisterUpgradePlans(app.migrations)
 
 	if loadLatest {
 		if err := app.LoadLatestVersion(); err != nil {
@@ -655,19 +767,95 @@ func (app *IrisApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci
 		panic(err)
 	}
 
-	// add system service at InitChainer, overwrite if it exists
+	if app.genesisDir != "" {
+		return app.initChainFromDir(ctx, genesisState)
+	}
+	if _, ok := genesisState[GenesisDirHashKey]; ok {
+		panic(fmt.Sprintf("the app state is exported to a genesis directory, which must be given with --%s", FlagGenesisDir))
+	}
+
+	genesisState[servicetypes.ModuleName] = app.addSystemServices(genesisState[servicetypes.ModuleName])
+	return app.mm.InitGenesis(ctx, app.appCodec, genesisState)
+}
+
+// initChainFromDir inits the modules in order from their genesis states in the chunked genesis directory,
+// reading the state of a module only when initializing it. The modules without a state in the directory
+// are initialized from the app state of the genesis file, if any. The hash of the directory must match
+// the one in the app state, so that every node initializes the chain from the same genesis.
+func (app *IrisApp) initChainFromDir(ctx sdk.Context, genesisState GenesisState) abci.ResponseInitChain {
+	if err := app.checkGenesisDir(genesisState); err != nil {
+		panic(err)
+	}
+
+	var validatorUpdates []abci.ValidatorUpdate
+	for _, moduleName := range app.mm.OrderInitGenesis {
+		state, err := ReadChunkedGenesisModule(app.genesisDir, moduleName)
+		if err != nil {
+			panic(err)
+		}
+		if state == nil {
+			state = genesisState[moduleName]
+		}
+		if moduleName == servicetypes.ModuleName {
+			state = app.addSystemServices(state)
+		}
+		if state == nil {
+			continue
+		}
+
+		moduleValUpdates := app.mm.Modules[moduleName].InitGenesis(ctx, app.appCodec, state)
+
+		// use these validator updates if provided, the module manager assumes
+		// only one module will update the validator set
+		if len(moduleValUpdates) > 0 {
+			if len(validatorUpdates) > 0 {
+				panic("validator InitGenesis updates already set by a previous module")
+			}
+			validatorUpdates = moduleValUpdates
+		}
+	}
+
+	return abci.ResponseInitChain{
+		Validators: validatorUpdates,
+	}
+}
+
+// HashGenesisDir returns the hash of the genesis states of all the modules in the chunked genesis directory
+func (app *IrisApp) HashGenesisDir(dir string) (string, error) {
+	return HashChunkedGenesisDir(dir, app.mm.OrderInitGenesis)
+}
+
+// checkGenesisDir returns an error if the hash of the genesis directory does not match the app state
+func (app *IrisApp) checkGenesisDir(genesisState GenesisState) error {
+	var expected string
+	if err := json.Unmarshal(genesisState[GenesisDirHashKey], &expected); err != nil || expected == "" {
+		return fmt.Errorf("the app state has no valid %s of the genesis directory %s", GenesisDirHashKey, app.genesisDir)
+	}
+	hash, err := app.HashGenesisDir(app.genesisDir)
+	if err != nil {
+		return err
+	}
+	if hash != expected {
+		return fmt.Errorf("hash %s of the genesis directory %s does not match the %s %s of the app state", hash, app.genesisDir, GenesisDirHashKey, expected)
+	}
+	return nil
+}
+
+// addSystemServices adds the system services to the service genesis state, overwriting them if they exist
+func (app *IrisApp) addSystemServices(state json.RawMessage) json.RawMessage {
 	var serviceGenState servicetypes.GenesisState
-	app.appCodec.MustUnmarshalJSON(genesisState[servicetypes.ModuleName], &serviceGenState)
+	app.appCodec.MustUnmarshalJSON(state, &serviceGenState)
 	serviceGenState.Definitions = append(serviceGenState.Definitions, servicetypes.GenOraclePriceSvcDefinition())
 	serviceGenState.Bindings = append(serviceGenState.Bindings, servicetypes.GenOraclePriceSvcBinding(nativeToken.MinUnit))
 	serviceGenState.Definitions = append(serviceGenState.Definitions, randomtypes.GetSvcDefinition())
-	genesisState[servicetypes.ModuleName] = app.appCodec.MustMarshalJSON(&serviceGenState)
-
-	return app.mm.InitGenesis(ctx, app.appCodec, genesisState)
+	return app.appCodec.MustMarshalJSON(&serviceGenState)
 }
 
-// LoadHeight loads a particular height
+// LoadHeight loads a particular height, which fails if the height is not committed or has been pruned
 func (app *IrisApp) LoadHeight(height int64) error {
+	if height <= 0 {
+		return fmt.Errorf("invalid height %d, which must be positive", height)
+	}
 	return app.LoadVersion(height)
 }
 
@@ -766,24 +954,33 @@ func (app *IrisApp) RegisterTendermintService(clientCtx client.Context) {
 	tmservice.RegisterTendermintService(app.BaseApp.GRPCQueryRouter(), clientCtx, app.interfaceRegistry)
 }
 
-// RegisterUpgradePlan implements the upgrade execution logic of the upgrade module
-func (app *IrisApp) RegisterUpgradePlan(
-	planName string,
-	upgrades *store.StoreUpgrades,
-	upgradeHandler sdkupgrade.UpgradeHandler,
-) {
+// RegisterUpgradePlans registers the upgrade handlers of all the plans, and configures the
+// store loader applying the store upgrades of the plan to be executed, if any
+func (app *IrisApp) RegisterUpgradePlans(registry *migrate.Registry) {
+	for _, plan := range registry.Plans() {
+		app.upgradeKeeper.SetUpgradeHandler(plan.Name, plan.Handler())
+	}
+
 	upgradeInfo, err := app.upgradeKeeper.ReadUpgradeInfoFromDisk()
 	if err != nil {
-		app.Logger().Info("not found upgrade plan", "planName", planName, "err", err.Error())
+		app.Logger().Info("not found upgrade plan", "err", err.Error())
 		return
 	}
 
-	if upgradeInfo.Name == planName && !app.upgradeKeeper.IsSkipHeight(upgradeInfo.Height) {
-		// this configures a no-op upgrade handler for the planName upgrade
-		app.upgradeKeeper.SetUpgradeHandler(planName, upgradeHandler)
-		// configure store loader that checks if version+1 == upgradeHeight and applies store upgrades
-		app.SetStoreLoader(sdkupgrade.UpgradeStoreLoader(upgradeInfo.Height, upgrades))
+	plan, ok := registry.Plan(upgradeInfo.Name)
+	if !ok || app.upgradeKeeper.IsSkipHeight(upgradeInfo.Height) {
+		return
 	}
+
+	app.Logger().Info("applying store upgrades", "planName", plan.Name, "height", upgradeInfo.Height)
+	// configure store loader that checks if version+1 == upgradeHeight and applies store upgrades
+	storeUpgrades := plan.StoreUpgrades
+	app.SetStoreLoader(upgradetypes.UpgradeStoreLoader(upgradeInfo.Height, &storeUpgrades))
+}
+
+// Migrations returns the registry of the upgrade plans
+func (app *IrisApp) Migrations() *migrate.Registry {
+	return app.migrations
 }
 
 // GetMaccPerms returns a copy of the module account permissions
@@ -815,6 +1012,8 @@ func initParamsKeeper(appCodec codec.BinaryMarshaler, legacyAmino *codec.LegacyA
 	paramsKeeper.Subspace(servicetypes.ModuleName)
 	paramsKeeper.Subspace(ibchost.ModuleName)
 	paramsKeeper.Subspace(farmtypes.ModuleName)
+	paramsKeeper.Subspace(globalfeetypes.ModuleName)
+	paramsKeeper.Subspace(surchargetypes.ModuleName)
 
 	return paramsKeeper
 }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"testing"
@@ -8,10 +9,25 @@ import (
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
 
+	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/simapp"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+
+	migratehtlc "github.com/irisnet/irishub/migrate/htlc"
+	globalfeetypes "github.com/irisnet/irishub/modules/globalfee/types"
+	memotypes "github.com/irisnet/irishub/modules/memo/types"
+	surchargetypes "github.com/irisnet/irishub/modules/surcharge/types"
 )
 
 func TestIrisAppExport(t *testing.T) {
@@ -35,9 +51,200 @@ func TestIrisAppExport(t *testing.T) {
 	app2 := NewIrisApp(log.NewTMLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, map[int64]bool{}, DefaultNodeHome, simapp.FlagPeriodValue, MakeEncodingConfig(), EmptyAppOptions{}, interBlockCacheOpt())
 	_, err = app2.ExportAppStateAndValidators(false, []string{})
 	require.NoError(t, err, "ExportAppStateAndValidators should not have an error")
+
+	modules, err := app2.ExportModuleNames([]string{"coinswap", "bank", "staking"}, []string{"staking"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bank", "coinswap"}, modules)
+	_, err = app2.ExportModuleNames(nil, []string{"unknown"})
+	require.Error(t, err)
+
+	exported, err := app2.ExportAppStateAndValidatorsOfModules(false, []string{}, modules)
+	require.NoError(t, err)
+	var appState GenesisState
+	require.NoError(t, json.Unmarshal(exported.AppState, &appState))
+	require.Len(t, appState, 2)
+	require.Contains(t, appState, "bank")
+	require.Contains(t, appState, "coinswap")
 }
 
 func TestGetMaccPerms(t *testing.T) {
 	dup := GetMaccPerms()
 	require.Equal(t, maccPerms, dup, "duplicated module account permissions differed from actual module account permissions")
 }
+
+func TestIrisAppExportAtHeight(t *testing.T) {
+	db := dbm.NewMemDB()
+	// keep the latest 2 heights only, pruning every height
+	pruning := baseapp.SetPruning(storetypes.NewPruningOptions(2, 0, 1))
+	app := setupTestApp(t, withDB(db), withBaseAppOptions(pruning))
+	for height := int64(2); height <= 6; height++ {
+		app.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: height}})
+		app.EndBlock(abci.RequestEndBlock{Height: height})
+		app.Commit()
+	}
+
+	newApp := func() *IrisApp {
+		return NewIrisApp(log.NewNopLogger(), db, nil, false, map[int64]bool{}, DefaultNodeHome, simapp.FlagPeriodValue, MakeEncodingConfig(), EmptyAppOptions{})
+	}
+
+	app2 := newApp()
+	require.NoError(t, app2.LoadHeight(5))
+	require.Equal(t, int64(5), app2.LastBlockHeight())
+	exported, err := app2.ExportAppStateAndValidators(false, []string{})
+	require.NoError(t, err)
+	require.Equal(t, int64(6), exported.Height)
+
+	app3 := newApp()
+	require.NoError(t, app3.LoadHeight(5))
+	exported, err = app3.ExportAppStateAndValidators(true, []string{})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), exported.Height)
+
+	require.Error(t, newApp().LoadHeight(2))
+	require.Error(t, newApp().LoadHeight(7))
+	require.Error(t, newApp().LoadHeight(0))
+}
+
+func TestRegisterUpgradePlans(t *testing.T) {
+	home := t.TempDir()
+	db := dbm.NewMemDB()
+	newApp := func() *IrisApp {
+		return NewIrisApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, home, simapp.FlagPeriodValue, MakeEncodingConfig(), EmptyAppOptions{})
+	}
+
+	app := setupTestApp(t, withDB(db), withHome(home))
+	plan, ok := app.Migrations().Plan("v1.2")
+	require.True(t, ok)
+	require.Equal(t, []string{memotypes.StoreKey}, plan.StoreUpgrades.Added)
+	for _, plan := range app.Migrations().Plans() {
+		require.True(t, app.upgradeKeeper.HasHandler(plan.Name), plan.Name)
+	}
+
+	// commit the height 2 without the memo store, as a chain started before it existed
+	mountStores := func(withMemo bool) *rootmulti.Store {
+		cms := rootmulti.NewStore(db)
+		for name, key := range app.keys {
+			if withMemo || name != memotypes.StoreKey {
+				cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+			}
+		}
+		require.NoError(t, cms.LoadLatestVersion())
+		return cms
+	}
+	require.Equal(t, int64(2), mountStores(false).Commit().Version)
+	memoPrefix := []byte("s/k:" + memotypes.StoreKey + "/")
+	it, err := db.Iterator(memoPrefix, sdk.PrefixEndBytes(memoPrefix))
+	require.NoError(t, err)
+	for ; it.Valid(); it.Next() {
+		require.NoError(t, db.Delete(it.Key()))
+	}
+	require.NoError(t, it.Close())
+
+	// upgrade at the height 3, the store loader adding the memo store
+	require.NoError(t, app.upgradeKeeper.DumpUpgradeInfoToDisk(3, plan.Name))
+	app = newApp()
+	header := tmproto.Header{Height: 3}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+
+	// the steps initialize the params of globalfee and surcharge, absent before the upgrade
+	ctx := app.NewContext(false, header)
+	paramStore := ctx.KVStore(app.keys[paramstypes.StoreKey])
+	paramKeys := [][]byte{
+		append([]byte(globalfeetypes.ModuleName+"/"), globalfeetypes.KeyMinimumGasPrices...),
+		append([]byte(surchargetypes.ModuleName+"/"), surchargetypes.KeySurcharges...),
+	}
+	for _, key := range paramKeys {
+		paramStore.Delete(key)
+	}
+	require.Empty(t, app.surchargeKeeper.GetParamSet(ctx).Surcharges)
+
+	app.upgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: plan.Name, Height: header.Height})
+	for _, key := range paramKeys {
+		require.True(t, paramStore.Has(key), string(key))
+	}
+	require.Equal(t, surchargetypes.DefaultParams(), app.surchargeKeeper.GetParamSet(ctx))
+
+	app.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	app.Commit()
+
+	// the versions of the memo store match the heights
+	cms := mountStores(true)
+	require.Equal(t, int64(3), cms.LastCommitID().Version)
+	require.Equal(t, int64(3), cms.GetCommitKVStore(app.keys[memotypes.StoreKey]).LastCommitID().Version)
+}
+
+func TestMigrateHTLCWithReport(t *testing.T) {
+	app := setupTestApp(t)
+	header := tmproto.Header{Height: 2}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := app.NewContext(false, header)
+	appCodec := app.AppCodec()
+
+	sender1, sender2, receiver := sdk.AccAddress("sender1_____________"), sdk.AccAddress("sender2_____________"), sdk.AccAddress("receiver____________")
+	newHTLC := func(sender sdk.AccAddress, amount int64, state migratehtlc.HTLCStatus) migratehtlc.OldHTLC {
+		return migratehtlc.OldHTLC{
+			Sender:           sender.String(),
+			To:               receiver.String(),
+			Amount:           sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, amount)),
+			ExpirationHeight: 100,
+			State:            state,
+		}
+	}
+	htlcs := []struct {
+		hashLock tmbytes.HexBytes
+		sender   sdk.AccAddress
+		htlc     migratehtlc.OldHTLC
+		newState htlctypes.HTLCState
+	}{
+		{bytes.Repeat([]byte{1}, 32), sender1, newHTLC(sender1, 10, migratehtlc.Open), htlctypes.Open},
+		{bytes.Repeat([]byte{2}, 32), sender1, newHTLC(sender1, 20, migratehtlc.Completed), htlctypes.Completed},
+		{bytes.Repeat([]byte{3}, 32), sender2, newHTLC(sender2, 30, migratehtlc.Expired), htlctypes.Refunded},
+		{bytes.Repeat([]byte{4}, 32), sender1, newHTLC(sender1, 40, migratehtlc.Expired), htlctypes.Refunded},
+		{bytes.Repeat([]byte{5}, 32), sender1, newHTLC(sender1, 50, migratehtlc.Expired), htlctypes.Refunded},
+		{bytes.Repeat([]byte{6}, 32), sender2, newHTLC(sender2, 60, migratehtlc.Refunded), htlctypes.Refunded},
+	}
+
+	// the module account holds the coins of the expired htlcs to refund
+	store := ctx.KVStore(app.keys[htlctypes.StoreKey])
+	for _, h := range htlcs {
+		store.Set(migratehtlc.GetHTLCKey(h.hashLock), appCodec.MustMarshalBinaryBare(&h.htlc))
+	}
+	require.NoError(t, app.bankKeeper.MintCoins(ctx, htlctypes.ModuleName, sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 120))))
+
+	params := migratehtlc.PresetHTLTParams()
+	params.AssetParams = params.AssetParams[:1]
+	report, err := migratehtlc.MigrateWithReport(ctx, appCodec, app.htlcKeeper, app.bankKeeper, app.keys[htlctypes.StoreKey], params)
+	require.NoError(t, err)
+
+	require.Len(t, report.HTLCs, len(htlcs))
+	for i, h := range htlcs {
+		id := htlctypes.GetID(h.sender, receiver, h.htlc.Amount, h.hashLock)
+		require.Equal(t, migratehtlc.HTLCReport{
+			HashLock: h.hashLock.String(),
+			ID:       id.String(),
+			Sender:   h.htlc.Sender,
+			OldState: h.htlc.State.String(),
+			NewState: h.newState.String(),
+		}, report.HTLCs[i])
+
+		migrated, found := app.htlcKeeper.GetHTLC(ctx, id)
+		require.True(t, found, id.String())
+		require.Equal(t, h.newState, migrated.State)
+		require.False(t, store.Has(migratehtlc.GetHTLCKey(h.hashLock)))
+	}
+
+	// the refunds of the expired htlcs are aggregated by sender
+	refunds := []migratehtlc.Refund{
+		{Sender: sender1.String(), Amount: sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 90))},
+		{Sender: sender2.String(), Amount: sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 30))},
+	}
+	if sender2.String() < sender1.String() {
+		refunds[0], refunds[1] = refunds[1], refunds[0]
+	}
+	require.Equal(t, refunds, report.Refunds)
+	require.Equal(t, sdk.NewInt64Coin(sdk.DefaultBondDenom, 90), app.bankKeeper.GetBalance(ctx, sender1, sdk.DefaultBondDenom))
+	require.Equal(t, sdk.NewInt64Coin(sdk.DefaultBondDenom, 30), app.bankKeeper.GetBalance(ctx, sender2, sdk.DefaultBondDenom))
+
+	require.Equal(t, params, report.Params)
+	require.Equal(t, params, app.htlcKeeper.GetParams(ctx))
+}
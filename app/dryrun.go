@@ -0,0 +1,166 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+
+	"github.com/irisnet/irishub/migrate"
+)
+
+// StoreChanges is the number of the keys of a store changed by an upgrade
+type StoreChanges struct {
+	Store   string `json:"store"`
+	Added   int    `json:"added"`
+	Updated int    `json:"updated"`
+	Deleted int    `json:"deleted"`
+}
+
+// BrokenInvariant is an invariant broken after an upgrade
+type BrokenInvariant struct {
+	Route   string `json:"route"`
+	Message string `json:"message"`
+}
+
+// DryRunResult is the result of running an upgrade plan without committing the state
+type DryRunResult struct {
+	Plan             string            `json:"plan"`
+	Height           int64             `json:"height"`
+	Report           migrate.Report    `json:"report"`
+	Error            string            `json:"error,omitempty"`
+	Changes          []StoreChanges    `json:"changes"`
+	BrokenInvariants []BrokenInvariant `json:"broken_invariants"`
+}
+
+// DryRunUpgrade runs the migration steps of the plan against the latest state in a cached
+// context, which is discarded, and checks all the crisis invariants afterwards. A failed step
+// is recorded in the result, along with the changes made by the steps run before it.
+// The steps are given the upgrade plan scheduled on chain if it has the same name, else the
+// info is the given one, e.g. of a plan not proposed yet.
+func (app *IrisApp) DryRunUpgrade(planName, info string) (DryRunResult, error) {
+	plan, ok := app.migrations.Plan(planName)
+	if !ok {
+		return DryRunResult{}, fmt.Errorf("upgrade plan %s is not registered", planName)
+	}
+
+	height := app.LastBlockHeight() + 1
+	// the migrations only write to the cached multistore, which is never written back
+	latestCtx := app.NewUncachedContext(false, tmproto.Header{Height: height, Time: tmtime.Now()})
+	ctx := latestCtx.WithMultiStore(latestCtx.MultiStore().CacheMultiStore())
+
+	upgradePlan, scheduled := app.upgradeKeeper.GetUpgradePlan(ctx)
+	if !scheduled || upgradePlan.Name != planName {
+		upgradePlan = upgradetypes.Plan{Name: planName, Height: height, Info: info}
+	} else if info != "" && info != upgradePlan.Info {
+		return DryRunResult{}, fmt.Errorf("upgrade plan %s is scheduled with another info: %s", planName, upgradePlan.Info)
+	}
+
+	result := DryRunResult{
+		Plan:             plan.Name,
+		Height:           height,
+		Changes:          []StoreChanges{},
+		BrokenInvariants: []BrokenInvariant{},
+	}
+
+	report, err := plan.Run(ctx, upgradePlan)
+	result.Report = report
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	names := make([]string, 0, len(app.keys))
+	for name := range app.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := app.keys[name]
+		changes := diffKVStores(latestCtx.KVStore(key), ctx.KVStore(key))
+		if changes.Added+changes.Updated+changes.Deleted == 0 {
+			continue
+		}
+		changes.Store = name
+		result.Changes = append(result.Changes, changes)
+	}
+
+	for _, route := range app.crisisKeeper.Routes() {
+		if msg, broken := checkInvariant(ctx, route.Invar); broken {
+			result.BrokenInvariants = append(result.BrokenInvariants, BrokenInvariant{
+				Route:   route.FullRoute(),
+				Message: msg,
+			})
+		}
+	}
+	return result, nil
+}
+
+// checkInvariant runs the invariant, recovering from its panic as a broken invariant
+func checkInvariant(ctx sdk.Context, invar sdk.Invariant) (msg string, broken bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, broken = fmt.Sprintf("invariant panicked: %v", r), true
+		}
+	}()
+	return invar(ctx)
+}
+
+// diffKVStores counts the keys added, updated and deleted in the target store compared to the source store
+func diffKVStores(source, target sdk.KVStore) (changes StoreChanges) {
+	walkKVStoreDiff(source, target, func(key, srcValue, dstValue []byte) bool {
+		switch {
+		case srcValue == nil:
+			changes.Added++
+		case dstValue == nil:
+			changes.Deleted++
+		default:
+			changes.Updated++
+		}
+		return false
+	})
+	return changes
+}
+
+// walkKVStoreDiff calls the callback in the key order for each key of the source and the target stores
+// whose value differs, with a nil value for the key missing in a store, until the callback returns true
+func walkKVStoreDiff(source, target sdk.KVStore, cb func(key, srcValue, dstValue []byte) (stop bool)) {
+	srcIter := source.Iterator(nil, nil)
+	defer srcIter.Close()
+	dstIter := target.Iterator(nil, nil)
+	defer dstIter.Close()
+
+	for srcIter.Valid() || dstIter.Valid() {
+		var stop bool
+		switch {
+		case !dstIter.Valid():
+			stop = cb(srcIter.Key(), srcIter.Value(), nil)
+			srcIter.Next()
+		case !srcIter.Valid():
+			stop = cb(dstIter.Key(), nil, dstIter.Value())
+			dstIter.Next()
+		default:
+			switch cmp := bytes.Compare(srcIter.Key(), dstIter.Key()); {
+			case cmp < 0:
+				stop = cb(srcIter.Key(), srcIter.Value(), nil)
+				srcIter.Next()
+			case cmp > 0:
+				stop = cb(dstIter.Key(), nil, dstIter.Value())
+				dstIter.Next()
+			default:
+				if !bytes.Equal(srcIter.Value(), dstIter.Value()) {
+					stop = cb(srcIter.Key(), srcIter.Value(), dstIter.Value())
+				}
+				srcIter.Next()
+				dstIter.Next()
+			}
+		}
+		if stop {
+			return
+		}
+	}
+}
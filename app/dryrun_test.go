@@ -0,0 +1,90 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+
+	"github.com/irisnet/irishub/migrate"
+	migratehtlc "github.com/irisnet/irishub/migrate/htlc"
+	memotypes "github.com/irisnet/irishub/modules/memo/types"
+)
+
+func TestDryRunUpgrade(t *testing.T) {
+	app := setupTestApp(t)
+
+	key := app.GetKey(memotypes.StoreKey)
+	var info string
+	app.Migrations().Register(migrate.Plan{
+		Name: "test",
+		Steps: []migrate.Step{{
+			Module: memotypes.ModuleName,
+			Name:   "write",
+			Migrate: func(ctx sdk.Context, plan upgradetypes.Plan) error {
+				info = plan.Info
+				ctx.KVStore(key).Set([]byte("key"), []byte("value"))
+				return nil
+			},
+		}},
+	})
+
+	result, err := app.DryRunUpgrade("test", "given")
+	require.NoError(t, err)
+	require.Equal(t, "given", info)
+	require.Empty(t, result.Error)
+	require.Equal(t, app.LastBlockHeight()+1, result.Height)
+	require.Len(t, result.Report.Steps, 1)
+	require.Equal(t, []StoreChanges{{Store: memotypes.StoreKey, Added: 1}}, result.Changes)
+	require.Empty(t, result.BrokenInvariants)
+
+	// the changes are discarded
+	ctx := app.NewUncachedContext(false, tmproto.Header{})
+	require.False(t, ctx.KVStore(key).Has([]byte("key")))
+
+	// the steps are given the info of the scheduled plan
+	ctx = app.NewUncachedContext(false, tmproto.Header{Height: app.LastBlockHeight() + 1})
+	require.NoError(t, app.upgradeKeeper.ScheduleUpgrade(ctx, upgradetypes.Plan{Name: "test", Height: 100, Info: "scheduled"}))
+	_, err = app.DryRunUpgrade("test", "")
+	require.NoError(t, err)
+	require.Equal(t, "scheduled", info)
+	_, err = app.DryRunUpgrade("test", "given")
+	require.Error(t, err)
+
+	result, err = app.DryRunUpgrade("v1.1", "")
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+
+	_, err = app.DryRunUpgrade("unknown", "")
+	require.Error(t, err)
+}
+
+func TestDryRunUpgradeHTLCParams(t *testing.T) {
+	app := setupTestApp(t)
+	cdc := app.AppCodec()
+
+	params := migratehtlc.PresetHTLTParams()
+	params.AssetParams = params.AssetParams[:1]
+	bz, err := cdc.MarshalJSON(&params)
+	require.NoError(t, err)
+
+	// the htlc params of the plan info are installed by the upgrade handler
+	plan, ok := app.Migrations().Plan("v1.1")
+	require.True(t, ok)
+	ctx := app.NewUncachedContext(false, tmproto.Header{Height: app.LastBlockHeight() + 1})
+	plan.Handler()(ctx, upgradetypes.Plan{Name: "v1.1", Info: fmt.Sprintf(`{"htlc_params":%s}`, bz)})
+	require.Equal(t, params, app.htlcKeeper.GetParams(ctx))
+
+	// invalid params fail the migration, which the dry run reports
+	params.AssetParams[0].Denom = ""
+	bz, err = cdc.MarshalJSON(&params)
+	require.NoError(t, err)
+	result, err := app.DryRunUpgrade("v1.1", fmt.Sprintf(`{"htlc_params":%s}`, bz))
+	require.NoError(t, err)
+	require.Contains(t, result.Error, "invalid htlc params of the upgrade plan info")
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/std"
 
 	"github.com/irisnet/irishub/app/params"
+	"github.com/irisnet/irishub/crypto/sm2"
 )
 
 // MakeEncodingConfig creates an EncodingConfig for testing
@@ -11,6 +12,8 @@ func MakeEncodingConfig() params.EncodingConfig {
 	encodingConfig := params.MakeEncodingConfig()
 	std.RegisterLegacyAminoCodec(encodingConfig.Amino)
 	std.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+	sm2.RegisterCrypto(encodingConfig.Amino)
+	sm2.RegisterInterfaces(encodingConfig.InterfaceRegistry)
 	ModuleBasics.RegisterLegacyAminoCodec(encodingConfig.Amino)
 	ModuleBasics.RegisterInterfaces(encodingConfig.InterfaceRegistry)
 	return encodingConfig
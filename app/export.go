@@ -2,6 +2,7 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
@@ -22,6 +23,37 @@ import (
 func (app *IrisApp) ExportAppStateAndValidators(
 	forZeroHeight bool, jailAllowedAddrs []string,
 ) (servertypes.ExportedApp, error) {
+	return app.ExportAppStateAndValidatorsOfModules(forZeroHeight, jailAllowedAddrs, nil)
+}
+
+// ExportAppStateAndValidatorsOfModules exports the state of the given modules only for a genesis file,
+// or the state of all the modules if none is given.
+func (app *IrisApp) ExportAppStateAndValidatorsOfModules(
+	forZeroHeight bool, jailAllowedAddrs []string, modules []string,
+) (servertypes.ExportedApp, error) {
+	genState := make(map[string]json.RawMessage)
+	exported, err := app.ExportModuleStates(forZeroHeight, jailAllowedAddrs, modules, func(module string, state json.RawMessage) error {
+		genState[module] = state
+		return nil
+	})
+	if err != nil {
+		return servertypes.ExportedApp{}, err
+	}
+
+	exported.AppState, err = json.MarshalIndent(genState, "", "  ")
+	return exported, err
+}
+
+// ExportModuleStates exports the state of the given modules, or of all the modules if none is given,
+// handing the genesis state of each module to the callback in the export order instead of gathering
+// them, so that the state of a module can be released once handled. The returned app has no app state.
+func (app *IrisApp) ExportModuleStates(
+	forZeroHeight bool, jailAllowedAddrs []string, modules []string, cb func(module string, state json.RawMessage) error,
+) (servertypes.ExportedApp, error) {
+	if len(modules) == 0 {
+		modules = app.mm.OrderExportGenesis
+	}
+
 	// as if they could withdraw from the start of the next block
 	ctx := app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
 
@@ -33,21 +65,58 @@ func (app *IrisApp) ExportAppStateAndValidators(
 		app.prepForZeroHeightGenesis(ctx, jailAllowedAddrs)
 	}
 
-	genState := app.mm.ExportGenesis(ctx, app.appCodec)
-	appState, err := json.MarshalIndent(genState, "", "  ")
-	if err != nil {
-		return servertypes.ExportedApp{}, err
+	for _, module := range modules {
+		m, ok := app.mm.Modules[module]
+		if !ok {
+			return servertypes.ExportedApp{}, fmt.Errorf("unknown module %s", module)
+		}
+		if err := cb(module, m.ExportGenesis(ctx, app.appCodec)); err != nil {
+			return servertypes.ExportedApp{}, err
+		}
 	}
 
 	validators, err := staking.WriteValidators(ctx, app.stakingKeeper)
 	return servertypes.ExportedApp{
-		AppState:        appState,
 		Validators:      validators,
 		Height:          height,
 		ConsensusParams: app.BaseApp.GetConsensusParams(ctx),
 	}, err
 }
 
+// ExportModuleNames returns the modules to export in the export order, which are the given modules or all
+// the modules if none is given, less the excluded ones
+func (app *IrisApp) ExportModuleNames(modules, excludeModules []string) ([]string, error) {
+	toSet := func(names []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			if _, ok := app.mm.Modules[name]; !ok {
+				return nil, fmt.Errorf("unknown module %s, expected one of %v", name, app.mm.OrderExportGenesis)
+			}
+			set[name] = true
+		}
+		return set, nil
+	}
+	included, err := toSet(modules)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := toSet(excludeModules)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, module := range app.mm.OrderExportGenesis {
+		if (len(modules) == 0 || included[module]) && !excluded[module] {
+			names = append(names, module)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no module left to export")
+	}
+	return names, nil
+}
+
 // prepare for fresh start at zero height
 // NOTE zero height genesis is a temporary feature which will be deprecated
 //      in favour of export at a block height
@@ -2,6 +2,15 @@ package app
 
 import (
 	"encoding/json"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+	servicetypes "github.com/irisnet/irismod/modules/service/types"
+
+	"github.com/irisnet/irishub/migrate"
+	migratehtlc "github.com/irisnet/irishub/migrate/htlc"
+	migrateservice "github.com/irisnet/irishub/migrate/service"
 )
 
 // The genesis state of the blockchain is represented here as a map of raw json
@@ -18,3 +27,28 @@ func NewDefaultGenesisState() GenesisState {
 	encCfg := MakeEncodingConfig()
 	return ModuleBasics.DefaultGenesis(encCfg.Marshaler)
 }
+
+// GenesisMigrations returns the migrations of the genesis files exported by the previous versions,
+// installing the given htlc params
+func GenesisMigrations(cdc codec.JSONMarshaler, htlcParams htlctypes.Params) *migrate.GenesisRegistry {
+	return migrate.NewGenesisRegistry().
+		Register(migrate.GenesisMigration{
+			Version: "v1.1",
+			Transformers: []migrate.GenesisTransformer{
+				{
+					Module: htlctypes.ModuleName,
+					Name:   "re-key htlcs by id and refund expired htlcs",
+					Transform: func(appState migrate.AppState) error {
+						return migratehtlc.MigrateGenesis(cdc, appState, htlcParams)
+					},
+				},
+				{
+					Module: servicetypes.ModuleName,
+					Name:   "move service tax account balances to fee collector",
+					Transform: func(appState migrate.AppState) error {
+						return migrateservice.MigrateGenesis(cdc, appState)
+					},
+				},
+			},
+		})
+}
@@ -0,0 +1,375 @@
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FlagGenesisDir defines the app option of the chunked genesis directory the chain is initialized from
+	FlagGenesisDir = "genesis-dir"
+
+	// GenesisDirHashKey is the key of the app state of a genesis doc exported with a chunked genesis
+	// directory, whose value is the hash of the directory the chain checks before initializing from it
+	GenesisDirHashKey = "genesis_dir_hash"
+
+	// DefaultGenesisChunkSize is the default max number of elements in a chunk of a genesis array
+	DefaultGenesisChunkSize = 10000
+
+	chunkFileExt = ".json"
+)
+
+// ChunkedGenesisWriter writes the genesis states of the modules to a directory one at a time. The state
+// of a module is written to <module>.json, except the elements of its arrays longer than the chunk size,
+// which are written in chunks of at most the chunk size to <module>.<path>.<n>.json. The arrays are
+// chunked at any depth, the path of a nested array joining the fields and the indexes leading to it,
+// e.g. nft.collections.0.nfts.1.json for the nfts of the first collection.
+//
+// The chunks bound the size of the files, not the memory: a module state is still written and read
+// as a whole, as it is exported and initialized at once. The directory is identified by the hash of
+// HashChunkedGenesisDir, which the exported genesis doc carries in its app state.
+type ChunkedGenesisWriter struct {
+	dir       string
+	chunkSize int
+}
+
+// NewChunkedGenesisWriter creates the directory and returns a ChunkedGenesisWriter writing into it,
+// which does not chunk the arrays if the chunk size is not positive
+func NewChunkedGenesisWriter(dir string, chunkSize int) (*ChunkedGenesisWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &ChunkedGenesisWriter{dir: dir, chunkSize: chunkSize}, nil
+}
+
+// WriteModule writes the genesis state of the module, which must be a JSON object. Nothing is written
+// for the modules without genesis state.
+func (w *ChunkedGenesisWriter) WriteModule(module string, state json.RawMessage) error {
+	if len(state) == 0 {
+		return nil
+	}
+	if w.chunkSize > 0 {
+		if err := expectDelim(json.NewDecoder(bytes.NewReader(state)), '{'); err != nil {
+			return fmt.Errorf("invalid genesis state of module %s: %s", module, err)
+		}
+		chunked, _, err := w.writeChunks(module, nil, bytes.TrimSpace(state))
+		if err != nil {
+			return err
+		}
+		state = chunked
+	}
+	return ioutil.WriteFile(filepath.Join(w.dir, module+chunkFileExt), state, 0644)
+}
+
+// writeChunks writes the elements of the arrays longer than the chunk size in the value to their chunks,
+// returning the value with these arrays emptied and whether it is changed
+func (w *ChunkedGenesisWriter) writeChunks(module string, path []string, value json.RawMessage) (json.RawMessage, bool, error) {
+	// an array longer than the chunk size takes more than twice as many bytes
+	if len(value) <= 2*w.chunkSize {
+		return value, false, nil
+	}
+	switch value[0] {
+	case '{':
+		return w.writeObjectChunks(module, path, value)
+	case '[':
+		return w.writeArrayChunks(module, path, value)
+	}
+	return value, false, nil
+}
+
+func (w *ChunkedGenesisWriter) writeObjectChunks(module string, path []string, value json.RawMessage) (json.RawMessage, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, false, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	changed := false
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		field := token.(string)
+
+		var fieldValue json.RawMessage
+		if err := dec.Decode(&fieldValue); err != nil {
+			return nil, false, err
+		}
+		fieldValue, fieldChanged, err := w.writeChunks(module, chunkPath(path, field), fieldValue)
+		if err != nil {
+			return nil, false, err
+		}
+		fields[field] = fieldValue
+		changed = changed || fieldChanged
+	}
+	if !changed {
+		return value, false, nil
+	}
+
+	bz, err := json.Marshal(fields)
+	return bz, true, err
+}
+
+func (w *ChunkedGenesisWriter) writeArrayChunks(module string, path []string, value json.RawMessage) (json.RawMessage, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, false, err
+	}
+
+	var elems []json.RawMessage
+	changed := false
+	for dec.More() {
+		var elem json.RawMessage
+		if err := dec.Decode(&elem); err != nil {
+			return nil, false, err
+		}
+		elem, elemChanged, err := w.writeChunks(module, chunkPath(path, strconv.Itoa(len(elems))), elem)
+		if err != nil {
+			return nil, false, err
+		}
+		elems = append(elems, elem)
+		changed = changed || elemChanged
+	}
+
+	if len(elems) <= w.chunkSize {
+		// the array fits in its parent
+		if !changed {
+			return value, false, nil
+		}
+		bz, err := json.Marshal(elems)
+		return bz, true, err
+	}
+
+	for n, start := 1, 0; start < len(elems); n, start = n+1, start+w.chunkSize {
+		end := start + w.chunkSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		bz, err := json.Marshal(elems[start:end])
+		if err != nil {
+			return nil, false, err
+		}
+		if err := ioutil.WriteFile(filepath.Join(w.dir, chunkFileName(module, path, n)), bz, 0644); err != nil {
+			return nil, false, fmt.Errorf("failed to write the chunks of %s.%s: %s", module, strings.Join(path, "."), err)
+		}
+	}
+	return json.RawMessage("[]"), true, nil
+}
+
+// ReadChunkedGenesisModule reads the genesis state of the module written by a ChunkedGenesisWriter,
+// appending the elements of the chunks to their arrays. It returns nil if the module has no state in
+// the directory.
+func ReadChunkedGenesisModule(dir, module string) (json.RawMessage, error) {
+	bz, err := ioutil.ReadFile(filepath.Join(dir, module+chunkFileExt))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	chunkFiles, err := filepath.Glob(filepath.Join(dir, module+".*.*"+chunkFileExt))
+	if err != nil {
+		return nil, err
+	}
+	if len(chunkFiles) == 0 {
+		return bz, nil
+	}
+
+	type chunkFile struct {
+		n    int
+		file string
+	}
+	chunks := make(map[string][]chunkFile)
+	for _, file := range chunkFiles {
+		path, n, err := parseChunkFileName(module, filepath.Base(file))
+		if err != nil {
+			return nil, err
+		}
+		chunks[path] = append(chunks[path], chunkFile{n: n, file: file})
+	}
+
+	root := &chunkNode{}
+	for path, files := range chunks {
+		sort.Slice(files, func(i, j int) bool { return files[i].n < files[j].n })
+
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for _, chunk := range files {
+			elems, err := ioutil.ReadFile(chunk.file)
+			if err != nil {
+				return nil, err
+			}
+			elems = bytes.TrimSpace(elems)
+			if len(elems) < 2 || elems[0] != '[' || elems[len(elems)-1] != ']' {
+				return nil, fmt.Errorf("invalid genesis chunk %s", chunk.file)
+			}
+			if buf.Len() > 1 && len(elems) > 2 {
+				buf.WriteByte(',')
+			}
+			buf.Write(elems[1 : len(elems)-1])
+		}
+		buf.WriteByte(']')
+		root.node(strings.Split(path, ".")).elems = buf.Bytes()
+	}
+
+	state, err := root.join(module, bytes.TrimSpace(bz))
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis state of module %s: %s", module, err)
+	}
+	return state, nil
+}
+
+// HashChunkedGenesisDir returns the hex encoded SHA256 hash of the genesis states of the modules in the
+// directory, i.e. of the names and the contents of the files read by ReadChunkedGenesisModule
+func HashChunkedGenesisDir(dir string, modules []string) (string, error) {
+	var files []string
+	for _, module := range modules {
+		moduleFile := filepath.Join(dir, module+chunkFileExt)
+		if _, err := os.Stat(moduleFile); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		chunkFiles, err := filepath.Glob(filepath.Join(dir, module+".*.*"+chunkFileExt))
+		if err != nil {
+			return "", err
+		}
+		files = append(append(files, moduleFile), chunkFiles...)
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, file := range files {
+		bz, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		digest := sha256.Sum256(bz)
+		hash.Write([]byte(filepath.Base(file)))
+		hash.Write(digest[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// chunkNode is a node of the tree of the chunked arrays of a module, keyed by the fields and the indexes
+type chunkNode struct {
+	elems    json.RawMessage
+	children map[string]*chunkNode
+}
+
+// node returns the node of the path below the node, adding the missing nodes
+func (node *chunkNode) node(path []string) *chunkNode {
+	for _, key := range path {
+		if node.children == nil {
+			node.children = make(map[string]*chunkNode)
+		}
+		child, ok := node.children[key]
+		if !ok {
+			child = &chunkNode{}
+			node.children[key] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// join replaces the emptied array of the node with its chunked elements, then joins the chunks of
+// the children into the fields or the elements of the value
+func (node *chunkNode) join(path string, value json.RawMessage) (json.RawMessage, error) {
+	if node.elems != nil {
+		value = node.elems
+	}
+	if len(node.children) == 0 {
+		return value, nil
+	}
+
+	switch {
+	case bytes.HasPrefix(value, []byte("{")):
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return nil, err
+		}
+		for field, child := range node.children {
+			fieldValue, ok := fields[field]
+			if !ok {
+				return nil, fmt.Errorf("no field %s.%s of the genesis chunks", path, field)
+			}
+			joined, err := child.join(path+"."+field, fieldValue)
+			if err != nil {
+				return nil, err
+			}
+			fields[field] = joined
+		}
+		return json.Marshal(fields)
+
+	case bytes.HasPrefix(value, []byte("[")):
+		var elems []json.RawMessage
+		if err := json.Unmarshal(value, &elems); err != nil {
+			return nil, err
+		}
+		for key, child := range node.children {
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(elems) {
+				return nil, fmt.Errorf("no element %s.%s of the genesis chunks", path, key)
+			}
+			joined, err := child.join(path+"."+key, elems[i])
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = joined
+		}
+		return json.Marshal(elems)
+	}
+	return nil, fmt.Errorf("%s of the genesis chunks is neither an object nor an array", path)
+}
+
+// chunkPath returns a copy of the path with the key of a field or an index appended
+func chunkPath(path []string, key string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), key)
+}
+
+func chunkFileName(module string, path []string, n int) string {
+	return fmt.Sprintf("%s.%s.%d%s", module, strings.Join(path, "."), n, chunkFileExt)
+}
+
+func parseChunkFileName(module, name string) (path string, n int, err error) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(name, module+"."), chunkFileExt), ".")
+	if len(parts) < 2 {
+		return "", 0, fmt.Errorf("invalid genesis chunk file name %s", name)
+	}
+	for _, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return "", 0, fmt.Errorf("invalid genesis chunk file name %s", name)
+		}
+	}
+	if n, err = strconv.Atoi(parts[len(parts)-1]); err != nil || n <= 0 {
+		return "", 0, fmt.Errorf("invalid genesis chunk file name %s", name)
+	}
+	return strings.Join(parts[:len(parts)-1], "."), n, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err == io.EOF {
+		return fmt.Errorf("expected %s, got EOF", delim)
+	}
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %s, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	nfttypes "github.com/irisnet/irismod/modules/nft/types"
+	recordtypes "github.com/irisnet/irismod/modules/record/types"
+)
+
+type genesisDirAppOptions string
+
+func (dir genesisDirAppOptions) Get(key string) interface{} {
+	if key == FlagGenesisDir {
+		return string(dir)
+	}
+	return nil
+}
+
+func TestChunkedGenesisWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewChunkedGenesisWriter(dir, 2)
+	require.NoError(t, err)
+
+	// the arrays are chunked at any depth, within the chunked arrays as well
+	state := json.RawMessage(`{"params":{"a":[1,2,3]},"small":[1,2],"large":[{"a":1},{"b":[1,2,3,4,5]},{"c":3},{"d":4},{"e":5}],"empty":[],"nested":[{"items":[1,2,3]},{"items":[4]}]}`)
+	require.NoError(t, writer.WriteModule("test", state))
+
+	files, err := filepath.Glob(filepath.Join(dir, "test.*"))
+	require.NoError(t, err)
+	for i := range files {
+		files[i] = filepath.Base(files[i])
+	}
+	require.ElementsMatch(t, []string{
+		"test.json",
+		"test.params.a.1.json", "test.params.a.2.json",
+		"test.large.1.json", "test.large.2.json", "test.large.3.json",
+		"test.large.1.b.1.json", "test.large.1.b.2.json", "test.large.1.b.3.json",
+		"test.nested.0.items.1.json", "test.nested.0.items.2.json",
+	}, files)
+
+	read, err := ReadChunkedGenesisModule(dir, "test")
+	require.NoError(t, err)
+	require.JSONEq(t, string(state), string(read))
+
+	read, err = ReadChunkedGenesisModule(dir, "missing")
+	require.NoError(t, err)
+	require.Nil(t, read)
+
+	// the chunks of a missing element are rejected
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "test.nested.5.items.1.json"), []byte("[1]"), 0644))
+	_, err = ReadChunkedGenesisModule(dir, "test")
+	require.Error(t, err)
+}
+
+func TestChunkedGenesisRoundTrip(t *testing.T) {
+	const (
+		numCollections = 2500
+		numNFTs        = 4
+		numRecords     = 10000
+		chunkSize      = 1000
+	)
+
+	encCfg := MakeEncodingConfig()
+	owner := sdk.AccAddress("owner_______________")
+
+	genesisState := NewDefaultGenesisState()
+
+	nftGenesis := nfttypes.NewGenesisState(make([]nfttypes.Collection, numCollections))
+	for i := range nftGenesis.Collections {
+		denomID := fmt.Sprintf("denom%d", i)
+		nfts := make([]nfttypes.BaseNFT, numNFTs)
+		if i == 0 {
+			// the nfts of a collection above the chunk size are chunked as well
+			nfts = make([]nfttypes.BaseNFT, chunkSize+numNFTs)
+		}
+		for j := range nfts {
+			nfts[j] = nfttypes.NewBaseNFT(fmt.Sprintf("nft%d", j), "name", owner, "https://nft.org", "data")
+		}
+		nftGenesis.Collections[i] = nfttypes.NewCollection(
+			nfttypes.NewDenom(denomID, denomID, "schema", "", owner, false, false), nil,
+		)
+		nftGenesis.Collections[i].NFTs = nfts
+	}
+	genesisState[nfttypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(nftGenesis)
+
+	recordGenesis := recordtypes.NewGenesisState(make([]recordtypes.Record, numRecords))
+	for i := range recordGenesis.Records {
+		recordGenesis.Records[i] = recordtypes.NewRecord(
+			[]byte(fmt.Sprintf("tx%d", i)),
+			[]recordtypes.Content{{Digest: fmt.Sprintf("digest%d", i), DigestAlgo: "sha256"}},
+			owner,
+		)
+	}
+	genesisState[recordtypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(recordGenesis)
+
+	app1 := setupTestApp(t, withEncodingConfig(encCfg), withGenesisState(t, genesisState))
+
+	dir := t.TempDir()
+	writer, err := NewChunkedGenesisWriter(dir, chunkSize)
+	require.NoError(t, err)
+	_, err = app1.ExportModuleStates(false, []string{}, nil, writer.WriteModule)
+	require.NoError(t, err)
+
+	chunks, err := filepath.Glob(filepath.Join(dir, "nft.collections.?.json"))
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	chunks, err = filepath.Glob(filepath.Join(dir, "nft.collections.0.nfts.*.json"))
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	chunks, err = filepath.Glob(filepath.Join(dir, "record.records.*.json"))
+	require.NoError(t, err)
+	require.Len(t, chunks, 10)
+
+	// init a new chain from the chunked directory, the app state of the genesis doc holding its hash
+	hash, err := app1.HashGenesisDir(dir)
+	require.NoError(t, err)
+	appStateBytes, err := json.Marshal(map[string]string{GenesisDirHashKey: hash})
+	require.NoError(t, err)
+	app2 := setupTestApp(t, withAppOptions(genesisDirAppOptions(dir)), withAppStateBytes(appStateBytes))
+
+	exportModule := func(app *IrisApp, module string) json.RawMessage {
+		exported, err := app.ExportAppStateAndValidatorsOfModules(false, []string{}, []string{module})
+		require.NoError(t, err)
+		var appState GenesisState
+		require.NoError(t, json.Unmarshal(exported.AppState, &appState))
+		return appState[module]
+	}
+
+	require.JSONEq(t, string(exportModule(app1, nfttypes.ModuleName)), string(exportModule(app2, nfttypes.ModuleName)))
+
+	// the records are keyed by the ids derived from the order of their insertion
+	exportRecords := func(app *IrisApp) []recordtypes.Record {
+		var exported recordtypes.GenesisState
+		encCfg.Marshaler.MustUnmarshalJSON(exportModule(app, recordtypes.ModuleName), &exported)
+		sort.Slice(exported.Records, func(i, j int) bool { return exported.Records[i].TxHash < exported.Records[j].TxHash })
+		return exported.Records
+	}
+	records := exportRecords(app2)
+	require.Len(t, records, numRecords)
+	require.Equal(t, exportRecords(app1), records)
+}
+
+func TestInitChainFromDirHash(t *testing.T) {
+	app1 := setupTestApp(t)
+	dir := t.TempDir()
+	writer, err := NewChunkedGenesisWriter(dir, DefaultGenesisChunkSize)
+	require.NoError(t, err)
+	_, err = app1.ExportModuleStates(false, []string{}, nil, writer.WriteModule)
+	require.NoError(t, err)
+
+	hash, err := app1.HashGenesisDir(dir)
+	require.NoError(t, err)
+	appStateBytes, err := json.Marshal(map[string]string{GenesisDirHashKey: hash})
+	require.NoError(t, err)
+
+	// the files not read by the chain are not hashed
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "genesis.json"), []byte("{}"), 0644))
+	rehashed, err := app1.HashGenesisDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, hash, rehashed)
+
+	require.NotPanics(t, func() {
+		setupTestApp(t, withAppOptions(genesisDirAppOptions(dir)), withAppStateBytes(appStateBytes))
+	})
+
+	// the app state must carry the hash of the directory
+	require.Panics(t, func() {
+		setupTestApp(t, withAppOptions(genesisDirAppOptions(dir)), withAppStateBytes([]byte("{}")))
+	})
+	// and the app state exported to a directory can not init the chain without it
+	require.Panics(t, func() { setupTestApp(t, withAppStateBytes(appStateBytes)) })
+
+	// a changed module state does not match the hash
+	bz, err := ioutil.ReadFile(filepath.Join(dir, "mint.json"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "mint.json"), append(bz, ' '), 0644))
+	require.Panics(t, func() {
+		setupTestApp(t, withAppOptions(genesisDirAppOptions(dir)), withAppStateBytes(appStateBytes))
+	})
+}
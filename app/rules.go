@@ -0,0 +1,167 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	ibctransfertypes "github.com/cosmos/cosmos-sdk/x/ibc/applications/transfer/types"
+
+	coinswaptypes "github.com/irisnet/irismod/modules/coinswap/types"
+	farmtypes "github.com/irisnet/irismod/modules/farm/types"
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+	servicetypes "github.com/irisnet/irismod/modules/service/types"
+)
+
+// LiquidityTokenFamily defines the denom family of the coinswap liquidity tokens
+var LiquidityTokenFamily = DenomFamily{
+	Name: "coinswap liquidity token",
+	Match: func(denom string) bool {
+		return strings.HasPrefix(denom, coinswaptypes.FormatUniABSPrefix)
+	},
+}
+
+// DenomFamily defines a group of denoms which are restricted unless explicitly allowed
+type DenomFamily struct {
+	Name  string
+	Match func(denom string) bool
+}
+
+// TokenRule defines the coins carried by a msg type and the restricted denom families it may carry
+type TokenRule struct {
+	Coins   func(msg sdk.Msg) sdk.Coins
+	Allowed []string
+}
+
+// allows returns true if the given denom family is allowed by the rule
+func (r TokenRule) allows(family string) bool {
+	for _, allowed := range r.Allowed {
+		if allowed == family {
+			return true
+		}
+	}
+	return false
+}
+
+// msgWrapper is implemented by the msgs which wrap other msgs, e.g. authz MsgExec
+type msgWrapper interface {
+	GetMessages() ([]sdk.Msg, error)
+}
+
+// TokenRules is a registry of TokenRule keyed by the msg type
+type TokenRules struct {
+	families []DenomFamily
+	rules    map[string]TokenRule
+}
+
+// NewTokenRules returns an empty TokenRules restricting the given denom families
+func NewTokenRules(families ...DenomFamily) *TokenRules {
+	return &TokenRules{
+		families: families,
+		rules:    make(map[string]TokenRule),
+	}
+}
+
+// RegisterRule registers the TokenRule for the type of the given msg
+func (tr *TokenRules) RegisterRule(msg sdk.Msg, rule TokenRule) *TokenRules {
+	tr.rules[proto.MessageName(msg)] = rule
+	return tr
+}
+
+// Validate checks that the msg, and every msg wrapped by it, carries only the allowed denom families
+func (tr *TokenRules) Validate(msg sdk.Msg) error {
+	if wrapper, ok := msg.(msgWrapper); ok {
+		msgs, err := wrapper.GetMessages()
+		if err != nil {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+		}
+		for _, m := range msgs {
+			if err := tr.Validate(m); err != nil {
+				return err
+			}
+		}
+	}
+
+	rule, ok := tr.rules[proto.MessageName(msg)]
+	if !ok {
+		return nil
+	}
+
+	for _, coin := range rule.Coins(msg) {
+		for _, family := range tr.families {
+			if family.Match(coin.Denom) && !rule.allows(family.Name) {
+				return sdkerrors.Wrapf(
+					sdkerrors.ErrInvalidRequest, "%s %s is not allowed in %s",
+					family.Name, coin.Denom, proto.MessageName(msg),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultTokenRules returns the TokenRules applied to the irishub msgs
+func DefaultTokenRules() *TokenRules {
+	return NewTokenRules(LiquidityTokenFamily).
+		RegisterRule(&ibctransfertypes.MsgTransfer{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return sdk.Coins{msg.(*ibctransfertypes.MsgTransfer).Token}
+			},
+		}).
+		RegisterRule(&govtypes.MsgSubmitProposal{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*govtypes.MsgSubmitProposal).InitialDeposit
+			},
+		}).
+		RegisterRule(&govtypes.MsgDeposit{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*govtypes.MsgDeposit).Amount
+			},
+		}).
+		RegisterRule(&htlctypes.MsgCreateHTLC{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*htlctypes.MsgCreateHTLC).Amount
+			},
+		}).
+		RegisterRule(&servicetypes.MsgBindService{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*servicetypes.MsgBindService).Deposit
+			},
+		}).
+		RegisterRule(&servicetypes.MsgUpdateServiceBinding{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*servicetypes.MsgUpdateServiceBinding).Deposit
+			},
+		}).
+		RegisterRule(&servicetypes.MsgCallService{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*servicetypes.MsgCallService).ServiceFeeCap
+			},
+		}).
+		RegisterRule(&servicetypes.MsgUpdateRequestContext{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return msg.(*servicetypes.MsgUpdateRequestContext).ServiceFeeCap
+			},
+		}).
+		RegisterRule(&farmtypes.MsgCreatePool{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				m := msg.(*farmtypes.MsgCreatePool)
+				return append(append(sdk.Coins{}, m.RewardPerBlock...), m.TotalReward...)
+			},
+		}).
+		RegisterRule(&farmtypes.MsgAdjustPool{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				m := msg.(*farmtypes.MsgAdjustPool)
+				return append(append(sdk.Coins{}, m.RewardPerBlock...), m.AdditionalReward...)
+			},
+		}).
+		RegisterRule(&farmtypes.MsgStake{}, TokenRule{
+			Coins: func(msg sdk.Msg) sdk.Coins {
+				return sdk.Coins{msg.(*farmtypes.MsgStake).Amount}
+			},
+			Allowed: []string{LiquidityTokenFamily.Name},
+		})
+}
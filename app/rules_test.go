@@ -0,0 +1,62 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	ibctransfertypes "github.com/cosmos/cosmos-sdk/x/ibc/applications/transfer/types"
+
+	farmtypes "github.com/irisnet/irismod/modules/farm/types"
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+	servicetypes "github.com/irisnet/irismod/modules/service/types"
+)
+
+// wrapperMsg mocks a msg which wraps other msgs, e.g. authz MsgExec
+type wrapperMsg struct {
+	*banktypes.MsgSend
+	msgs []sdk.Msg
+}
+
+func (m wrapperMsg) GetMessages() ([]sdk.Msg, error) {
+	return m.msgs, nil
+}
+
+func TestTokenRules(t *testing.T) {
+	native := sdk.NewCoins(sdk.NewInt64Coin("uiris", 100))
+	lpt := sdk.NewCoins(sdk.NewInt64Coin("swapatom", 100))
+
+	rules := DefaultTokenRules()
+
+	tests := []struct {
+		name    string
+		msg     sdk.Msg
+		expPass bool
+	}{
+		{"bank send lpt", &banktypes.MsgSend{Amount: lpt}, true},
+		{"ibc transfer native", &ibctransfertypes.MsgTransfer{Token: native[0]}, true},
+		{"ibc transfer lpt", &ibctransfertypes.MsgTransfer{Token: lpt[0]}, false},
+		{"gov deposit lpt", &govtypes.MsgDeposit{Amount: lpt}, false},
+		{"htlc native", &htlctypes.MsgCreateHTLC{Amount: native}, true},
+		{"htlc lpt", &htlctypes.MsgCreateHTLC{Amount: lpt}, false},
+		{"service deposit lpt", &servicetypes.MsgBindService{Deposit: lpt}, false},
+		{"service fee cap lpt", &servicetypes.MsgCallService{ServiceFeeCap: lpt}, false},
+		{"farm reward lpt", &farmtypes.MsgCreatePool{TotalReward: native, RewardPerBlock: lpt}, false},
+		{"farm stake lpt", &farmtypes.MsgStake{Amount: lpt[0]}, true},
+		{"wrapped native", wrapperMsg{msgs: []sdk.Msg{&htlctypes.MsgCreateHTLC{Amount: native}}}, true},
+		{"wrapped lpt", wrapperMsg{msgs: []sdk.Msg{&htlctypes.MsgCreateHTLC{Amount: lpt}}}, false},
+		{"nested wrapped lpt", wrapperMsg{msgs: []sdk.Msg{wrapperMsg{msgs: []sdk.Msg{&govtypes.MsgDeposit{Amount: lpt}}}}}, false},
+	}
+
+	for _, tc := range tests {
+		err := rules.Validate(tc.msg)
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	"github.com/cosmos/cosmos-sdk/simapp"
+
+	irisappparams "github.com/irisnet/irishub/app/params"
+)
+
+// testAppConfig defines the fixture of the app created by setupTestApp
+type testAppConfig struct {
+	db             dbm.DB
+	home           string
+	encodingConfig irisappparams.EncodingConfig
+	appOpts        servertypes.AppOptions
+	baseAppOpts    []func(*baseapp.BaseApp)
+	appStateBytes  []byte
+}
+
+// testAppOption customizes the fixture of the app created by setupTestApp
+type testAppOption func(*testAppConfig)
+
+// withDB creates the app on the given db instead of a new memory db
+func withDB(db dbm.DB) testAppOption {
+	return func(cfg *testAppConfig) { cfg.db = db }
+}
+
+// withHome creates the app under the given home instead of the default one
+func withHome(home string) testAppOption {
+	return func(cfg *testAppConfig) { cfg.home = home }
+}
+
+// withEncodingConfig creates the app with the given encoding config
+func withEncodingConfig(encodingConfig irisappparams.EncodingConfig) testAppOption {
+	return func(cfg *testAppConfig) { cfg.encodingConfig = encodingConfig }
+}
+
+// withAppOptions creates the app with the given app options instead of empty ones
+func withAppOptions(appOpts servertypes.AppOptions) testAppOption {
+	return func(cfg *testAppConfig) { cfg.appOpts = appOpts }
+}
+
+// withBaseAppOptions creates the app with the given base app options
+func withBaseAppOptions(baseAppOpts ...func(*baseapp.BaseApp)) testAppOption {
+	return func(cfg *testAppConfig) { cfg.baseAppOpts = append(cfg.baseAppOpts, baseAppOpts...) }
+}
+
+// withGenesisState inits the chain with the given genesis state instead of the default one
+func withGenesisState(t *testing.T, genesisState GenesisState) testAppOption {
+	stateBytes, err := json.Marshal(genesisState)
+	require.NoError(t, err)
+	return withAppStateBytes(stateBytes)
+}
+
+// withAppStateBytes inits the chain with the given app state
+func withAppStateBytes(stateBytes []byte) testAppOption {
+	return func(cfg *testAppConfig) { cfg.appStateBytes = stateBytes }
+}
+
+// setupTestApp returns an app whose chain is initialized with the default genesis state
+// and committed at the height 1
+func setupTestApp(t *testing.T, opts ...testAppOption) *IrisApp {
+	cfg := testAppConfig{
+		db:             dbm.NewMemDB(),
+		home:           DefaultNodeHome,
+		encodingConfig: MakeEncodingConfig(),
+		appOpts:        EmptyAppOptions{},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.appStateBytes == nil {
+		withGenesisState(t, NewDefaultGenesisState())(&cfg)
+	}
+
+	app := NewIrisApp(log.NewNopLogger(), cfg.db, nil, true, map[int64]bool{}, cfg.home, simapp.FlagPeriodValue, cfg.encodingConfig, cfg.appOpts, cfg.baseAppOpts...)
+	app.InitChain(abci.RequestInitChain{Validators: []abci.ValidatorUpdate{}, AppStateBytes: cfg.appStateBytes})
+	app.Commit()
+	return app
+}
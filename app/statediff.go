@@ -0,0 +1,122 @@
+package app
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/types/kv"
+)
+
+// kinds of the state changes
+const (
+	ChangeAdded   = "added"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// StateChange is a key of a store, or a path in the genesis state of a module, changed between two states.
+// The values are given either decoded, or as is if they cannot be decoded.
+type StateChange struct {
+	Key     string `json:"key"`
+	Change  string `json:"change"`
+	Decoded string `json:"decoded,omitempty"`
+	A       string `json:"a,omitempty"`
+	B       string `json:"b,omitempty"`
+}
+
+// StateDiff is the changes of a store or of the genesis state of a module between two states
+type StateDiff struct {
+	Name      string        `json:"name"`
+	Added     int           `json:"added"`
+	Updated   int           `json:"updated"`
+	Deleted   int           `json:"deleted"`
+	Changes   []StateChange `json:"changes"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// Add counts the change, which is listed unless the diff already lists the max number of changes
+func (d *StateDiff) Add(change StateChange, maxChanges int) {
+	switch change.Change {
+	case ChangeAdded:
+		d.Added++
+	case ChangeUpdated:
+		d.Updated++
+	case ChangeDeleted:
+		d.Deleted++
+	}
+	if maxChanges > 0 && len(d.Changes) >= maxChanges {
+		d.Truncated = true
+		return
+	}
+	d.Changes = append(d.Changes, change)
+}
+
+// DiffStates compares the loaded states of the two apps store by store, decoding the changed pairs with
+// the store decoders of the simulation manager. At most maxChanges changes are listed per store, or all
+// of them if maxChanges is not positive. The stores are filtered by their names if any is given.
+func DiffStates(a, b *IrisApp, maxChanges int, stores ...string) ([]StateDiff, error) {
+	names := make([]string, 0, len(a.keys))
+	for name := range a.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(stores) > 0 {
+		for _, store := range stores {
+			if _, ok := a.keys[store]; !ok {
+				return nil, fmt.Errorf("unknown store %s, expected one of %v", store, names)
+			}
+		}
+		names = stores
+	}
+
+	ctxA := a.NewUncachedContext(false, tmproto.Header{Height: a.LastBlockHeight()})
+	ctxB := b.NewUncachedContext(false, tmproto.Header{Height: b.LastBlockHeight()})
+
+	diffs := []StateDiff{}
+	for _, name := range names {
+		diff := StateDiff{Name: name, Changes: []StateChange{}}
+		decoder := a.sm.StoreDecoders[name]
+		walkKVStoreDiff(ctxA.KVStore(a.keys[name]), ctxB.KVStore(b.keys[name]), func(key, valueA, valueB []byte) bool {
+			diff.Add(newStoreChange(decoder, key, valueA, valueB), maxChanges)
+			return false
+		})
+		if diff.Added+diff.Updated+diff.Deleted > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+func newStoreChange(decoder func(kvA, kvB kv.Pair) string, key, valueA, valueB []byte) StateChange {
+	change := StateChange{Key: hex.EncodeToString(key), Change: ChangeUpdated}
+	switch {
+	case valueA == nil:
+		change.Change = ChangeAdded
+	case valueB == nil:
+		change.Change = ChangeDeleted
+	}
+
+	if decoder != nil {
+		if decoded, ok := decodeStorePair(decoder, key, valueA, valueB); ok {
+			change.Decoded = decoded
+			return change
+		}
+	}
+	change.A = hex.EncodeToString(valueA)
+	change.B = hex.EncodeToString(valueB)
+	return change
+}
+
+// decodeStorePair decodes the pair with the decoder, which panics on the keys it does not know
+func decodeStorePair(decoder func(kvA, kvB kv.Pair) string, key, valueA, valueB []byte) (decoded string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			decoded, ok = "", false
+		}
+	}()
+	return decoder(kv.Pair{Key: key, Value: valueA}, kv.Pair{Key: key, Value: valueB}), true
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	memotypes "github.com/irisnet/irishub/modules/memo/types"
+)
+
+func TestDiffStates(t *testing.T) {
+	a, b := setupTestApp(t), setupTestApp(t)
+
+	diffs, err := DiffStates(a, b, 0)
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+
+	store := func(app *IrisApp) func(key, value string) {
+		ctx := app.NewUncachedContext(false, tmproto.Header{})
+		return func(key, value string) { ctx.KVStore(app.GetKey(memotypes.StoreKey)).Set([]byte(key), []byte(value)) }
+	}
+	store(a)("updated", "a")
+	store(a)("deleted", "a")
+	store(b)("updated", "b")
+	store(b)("added", "b")
+
+	diffs, err = DiffStates(a, b, 0)
+	require.NoError(t, err)
+	require.Equal(t, []StateDiff{{
+		Name:    memotypes.StoreKey,
+		Added:   1,
+		Updated: 1,
+		Deleted: 1,
+		Changes: []StateChange{
+			{Key: hex.EncodeToString([]byte("added")), Change: ChangeAdded, B: hex.EncodeToString([]byte("b"))},
+			{Key: hex.EncodeToString([]byte("deleted")), Change: ChangeDeleted, A: hex.EncodeToString([]byte("a"))},
+			{Key: hex.EncodeToString([]byte("updated")), Change: ChangeUpdated, A: hex.EncodeToString([]byte("a")), B: hex.EncodeToString([]byte("b"))},
+		},
+	}}, diffs)
+
+	diffs, err = DiffStates(a, b, 1, memotypes.StoreKey)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Len(t, diffs[0].Changes, 1)
+	require.True(t, diffs[0].Truncated)
+
+	_, err = DiffStates(a, b, 0, "unknown")
+	require.Error(t, err)
+}
+
+func TestDiffStatesDecoded(t *testing.T) {
+	a, b := setupTestApp(t), setupTestApp(t)
+	ctxA := a.NewUncachedContext(false, tmproto.Header{})
+	ctxB := b.NewUncachedContext(false, tmproto.Header{})
+
+	addr := sdk.AccAddress("updated_____________")
+	a.accountKeeper.SetAccount(ctxA, a.accountKeeper.NewAccountWithAddress(ctxA, addr))
+	accB := b.accountKeeper.NewAccountWithAddress(ctxB, addr)
+	require.NoError(t, accB.SetSequence(5))
+	b.accountKeeper.SetAccount(ctxB, accB)
+
+	// the decoder decodes an added account, and fails on an unknown key
+	b.accountKeeper.SetAccount(ctxB, b.accountKeeper.NewAccountWithAddress(ctxB, sdk.AccAddress("added_______________")))
+	ctxB.KVStore(b.GetKey(authtypes.StoreKey)).Set([]byte("unknown"), []byte("b"))
+
+	diffs, err := DiffStates(a, b, 0, authtypes.StoreKey)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, 2, diffs[0].Added)
+	require.Equal(t, 2, diffs[0].Updated)
+
+	changes := make(map[string]StateChange)
+	for _, change := range diffs[0].Changes {
+		changes[change.Key] = change
+	}
+
+	updated := changes[hex.EncodeToString(authtypes.AddressStoreKey(addr))]
+	require.Equal(t, ChangeUpdated, updated.Change)
+	require.Contains(t, updated.Decoded, `sequence: "0"`)
+	require.Contains(t, updated.Decoded, `sequence: "5"`)
+	require.Empty(t, updated.A)
+	require.Empty(t, updated.B)
+
+	accountNumber := changes[hex.EncodeToString(authtypes.GlobalAccountNumberKey)]
+	require.Equal(t, ChangeUpdated, accountNumber.Change)
+	require.Contains(t, accountNumber.Decoded, fmt.Sprintf("GlobalAccNumberA: {%d ", accB.GetAccountNumber()+1))
+	require.Contains(t, accountNumber.Decoded, fmt.Sprintf("GlobalAccNumberB: {%d ", accB.GetAccountNumber()+2))
+
+	added := changes[hex.EncodeToString(authtypes.AddressStoreKey(sdk.AccAddress("added_______________")))]
+	require.Equal(t, ChangeAdded, added.Change)
+	require.Contains(t, added.Decoded, sdk.AccAddress("added_______________").String())
+	require.Empty(t, added.B)
+
+	unknown := changes[hex.EncodeToString([]byte("unknown"))]
+	require.Equal(t, StateChange{Key: hex.EncodeToString([]byte("unknown")), Change: ChangeAdded, B: hex.EncodeToString([]byte("b"))}, unknown)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	genutilcli "github.com/cosmos/cosmos-sdk/x/genutil/client/cli"
+
+	"github.com/irisnet/irishub/address"
+	"github.com/irisnet/irishub/app"
+)
+
+var bech32PrefixConfigRegexp = regexp.MustCompile(`(?m)^(# The bech32 prefix root.*\n)?bech32-prefix\s*=.*$`)
+
+// configureBech32Prefix applies the bech32 prefix root resolved from the --bech32-prefix flag,
+// the IRIS_BECH32_PREFIX env var or the app config, in that order, and then seals the sdk config
+func configureBech32Prefix(cmd *cobra.Command) error {
+	root := address.Bech32ChainPrefix
+	switch {
+	case cmd.Flags().Changed(address.FlagBech32PrefixRoot):
+		root, _ = cmd.Flags().GetString(address.FlagBech32PrefixRoot)
+	case os.Getenv(address.EnvBech32PrefixRoot) != "":
+		root = os.Getenv(address.EnvBech32PrefixRoot)
+	case server.GetServerContextFromCmd(cmd).Viper.GetString(address.FlagBech32PrefixRoot) != "":
+		root = server.GetServerContextFromCmd(cmd).Viper.GetString(address.FlagBech32PrefixRoot)
+	}
+
+	if err := app.SetBech32PrefixRoot(root); err != nil {
+		return err
+	}
+	sdk.GetConfig().Seal()
+	return nil
+}
+
+// writeBech32PrefixConfig writes the bech32 prefix root into the app config of the home,
+// replacing the existing one if any
+func writeBech32PrefixConfig(home, root string) error {
+	if err := address.ValidateBech32PrefixRoot(root); err != nil {
+		return err
+	}
+
+	appConfigFile := filepath.Join(home, "config", "app.toml")
+	bz, err := ioutil.ReadFile(appConfigFile)
+	if err != nil {
+		return err
+	}
+
+	config := fmt.Sprintf("# The bech32 prefix root of the addresses, e.g. \"i\" for iaa/iva/ica.\nbech32-prefix = \"%s\"", root)
+	if bech32PrefixConfigRegexp.Match(bz) {
+		bz = bech32PrefixConfigRegexp.ReplaceAllLiteral(bz, []byte(config))
+	} else {
+		bz = append([]byte(config+"\n\n"), bz...)
+	}
+	return ioutil.WriteFile(appConfigFile, bz, 0644)
+}
+
+// initCmd wraps the genutil init command to write the bech32 prefix root into the app config
+func initCmd(mbm module.BasicManager, defaultNodeHome string) *cobra.Command {
+	cmd := genutilcli.InitCmd(mbm, defaultNodeHome)
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := runE(cmd, args); err != nil {
+			return err
+		}
+		home, _ := cmd.Flags().GetString(flags.FlagHome)
+		return writeBech32PrefixConfig(home, address.GetBech32PrefixRoot())
+	}
+	return cmd
+}
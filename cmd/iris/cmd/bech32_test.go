@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBech32PrefixConfig(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(home, "config"), 0755))
+	appConfigFile := filepath.Join(home, "config", "app.toml")
+	require.NoError(t, ioutil.WriteFile(appConfigFile, []byte("minimum-gas-prices = \"\"\n\n[api]\nenable = false\n"), 0644))
+
+	const expected = `# The bech32 prefix root of the addresses, e.g. "i" for iaa/iva/ica.
+bech32-prefix = "%s"
+
+minimum-gas-prices = ""
+
+[api]
+enable = false
+`
+
+	// the prefix root is replaced in place, without adding a line on each run
+	for _, root := range []string{"i", "i", "test", "test"} {
+		require.NoError(t, writeBech32PrefixConfig(home, root))
+		bz, err := ioutil.ReadFile(appConfigFile)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf(expected, root), string(bz))
+	}
+
+	require.Error(t, writeBech32PrefixConfig(home, "Invalid"))
+}
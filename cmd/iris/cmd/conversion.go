@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	converterspec "github.com/irisnet/irishub/converter"
+)
+
+// the conversion specs of the commands built into iris
+func init() {
+	converterspec.Register("global",
+		converterspec.Spec{Flags: []string{"fees", "amount", "deposit", "service-fee-cap"}},
+	)
+	converterspec.Register("bank",
+		converterspec.Spec{Module: "bank", Command: "send", Args: []int{2}},
+		converterspec.Spec{Module: "bank", Command: "balances", Paths: []string{"balances"}},
+		converterspec.Spec{Module: "bank", Command: "total", Paths: []string{"supply"}},
+	)
+	converterspec.Register("staking",
+		converterspec.Spec{Module: "staking", Command: "delegate", Args: []int{1}},
+		converterspec.Spec{Module: "staking", Command: "redelegate", Args: []int{2}},
+		converterspec.Spec{Module: "staking", Command: "unbond", Args: []int{1}},
+	)
+	converterspec.Register("distribution",
+		converterspec.Spec{Module: "distribution", Command: "fund-community-pool", Args: []int{0}},
+		converterspec.Spec{Module: "distribution", Command: "validator-outstanding-rewards", Paths: []string{"rewards"}},
+		converterspec.Spec{Module: "distribution", Command: "rewards", Paths: []string{"total", "rewards.*.reward", "rewards"}},
+	)
+	converterspec.Register("gov",
+		converterspec.Spec{Module: "gov", Command: "deposit", Args: []int{1}},
+		converterspec.Spec{Module: "gov", Command: "params", Paths: []string{"deposit_params.min_deposit"}},
+	)
+	converterspec.Register("token",
+		converterspec.Spec{Module: "token", Command: "total-burn", Paths: []string{"burned_coins"}},
+	)
+	converterspec.Register("farm",
+		converterspec.Spec{Module: "farm", Command: "create", Flags: []string{"reward-per-block", "total-reward"}},
+		converterspec.Spec{Module: "farm", Command: "adjust", Flags: []string{"reward-per-block", "additional-reward"}},
+		converterspec.Spec{Module: "farm", Command: "stake", Args: []int{1}},
+		converterspec.Spec{Module: "farm", Command: "unstake", Args: []int{1}},
+		converterspec.Spec{Module: "farm", Command: "pools", Paths: []string{"pools.*.total_lp_token_locked", "pools.*.total_reward", "pools.*.remaining_reward", "pools.*.reward_per_block"}},
+		converterspec.Spec{Module: "farm", Command: "pool", Paths: []string{"pool.total_lp_token_locked", "pool.total_reward", "pool.remaining_reward", "pool.reward_per_block"}},
+		converterspec.Spec{Module: "farm", Command: "farmer", Paths: []string{"list.*.locked", "list.*.pending_reward"}},
+		converterspec.Spec{Module: "farm", Command: "params", Paths: []string{"create_pool_fee"}},
+	)
+	converterspec.Register("htlc",
+		converterspec.Spec{Module: "htlc", Command: "htlc", Paths: []string{"amount"}},
+		converterspec.Spec{Module: "htlc", Command: "supply", Paths: []string{"incoming_supply", "outgoing_supply", "current_supply"}},
+		converterspec.Spec{Module: "htlc", Command: "supplies", Paths: []string{"asset_supplies.*.incoming_supply", "asset_supplies.*.outgoing_supply", "asset_supplies.*.current_supply"}},
+	)
+	converterspec.Register("service",
+		converterspec.Spec{Module: "service", Command: "binding", Paths: []string{"deposit"}},
+		converterspec.Spec{Module: "service", Command: "bindings", Paths: []string{"service_bindings.*.deposit"}},
+		converterspec.Spec{Module: "service", Command: "request", Paths: []string{"service_fee"}},
+		converterspec.Spec{Module: "service", Command: "request-context", Paths: []string{"service_fee_cap"}},
+		converterspec.Spec{Module: "service", Command: "fees", Paths: []string{"fees"}},
+		converterspec.Spec{Module: "service", Command: "params", Paths: []string{"min_deposit"}},
+	)
+}
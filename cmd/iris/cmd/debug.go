@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client/debug"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/version"
+)
+
+// flags of the addr-convert command
+const (
+	flagTo     = "to"
+	flagPrefix = "prefix"
+)
+
+// address formats of the addr-convert command
+const (
+	addrFormatAcc  = "acc"
+	addrFormatVal  = "val"
+	addrFormatCons = "cons"
+	addrFormatHex  = "hex"
+)
+
+// debugCmd returns the debug command extended with the iris tools
+func debugCmd() *cobra.Command {
+	cmd := debug.Cmd()
+	cmd.AddCommand(
+		addrConvertCmd(),
+		stateDiffCmd(),
+	)
+	return cmd
+}
+
+func addrConvertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addr-convert <address>",
+		Short: "Convert an address between the bech32 prefixes and hex",
+		Long: `Convert an address given in bech32 with any prefix, or in hex, to the account, validator
+or consensus address of this chain, to hex, or to bech32 with an arbitrary prefix.`,
+		Example: fmt.Sprintf(`$ %[1]s debug addr-convert cosmos1ljemm0yznz58qxxs8xyak7fashcfxf5laa4nsh
+$ %[1]s debug addr-convert iaa1ljemm0yznz58qxxs8xyak7fashcfxf5lgl4zjx --to val
+$ %[1]s debug addr-convert FCB3BDBC8298A87018D03989DB793D85F093269F --prefix osmo`, version.AppName),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := parseAnyAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			to, _ := cmd.Flags().GetString(flagTo)
+			prefix, _ := cmd.Flags().GetString(flagPrefix)
+			if prefix != "" {
+				if cmd.Flags().Changed(flagTo) {
+					return fmt.Errorf("--%s and --%s can not be used together", flagTo, flagPrefix)
+				}
+				addr, err := bech32.ConvertAndEncode(prefix, bz)
+				if err != nil {
+					return err
+				}
+				cmd.Println(addr)
+				return nil
+			}
+
+			switch to {
+			case addrFormatAcc:
+				cmd.Println(sdk.AccAddress(bz).String())
+			case addrFormatVal:
+				cmd.Println(sdk.ValAddress(bz).String())
+			case addrFormatCons:
+				cmd.Println(sdk.ConsAddress(bz).String())
+			case addrFormatHex:
+				cmd.Println(strings.ToUpper(hex.EncodeToString(bz)))
+			default:
+				return fmt.Errorf("invalid format %s, must be one of %s|%s|%s|%s", to, addrFormatAcc, addrFormatVal, addrFormatCons, addrFormatHex)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagTo, addrFormatAcc, fmt.Sprintf("The format to convert to (%s|%s|%s|%s)", addrFormatAcc, addrFormatVal, addrFormatCons, addrFormatHex))
+	cmd.Flags().String(flagPrefix, "", "Convert to bech32 with this arbitrary prefix instead, e.g. cosmos or osmo")
+	return cmd
+}
+
+// parseAnyAddress returns the bytes of an address given in bech32 with any prefix or in hex
+func parseAnyAddress(addr string) ([]byte, error) {
+	_, bz, err := bech32.DecodeAndConvert(addr)
+	if err != nil {
+		if bz, err = hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")); err != nil {
+			return nil, fmt.Errorf("invalid address %s, must be bech32 or hex", addr)
+		}
+	}
+
+	if err := verifyAnyAddressFormat(bz); err != nil {
+		return nil, fmt.Errorf("invalid address %s: %s", addr, err)
+	}
+	return bz, nil
+}
+
+// verifyAnyAddressFormat accepts the 32 bytes addresses, e.g. of the interchain accounts,
+// besides the addresses accepted by sdk.VerifyAddressFormat
+func verifyAnyAddressFormat(bz []byte) error {
+	if len(bz) == 32 {
+		return nil
+	}
+	return sdk.VerifyAddressFormat(bz)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrConvertCmd(t *testing.T) {
+	const (
+		addrHex    = "FCB3BDBC8298A87018D03989DB793D85F093269F"
+		addrCosmos = "cosmos1ljemm0yznz58qxxs8xyak7fashcfxf5laa4nsh"
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{"cosmos to acc", []string{addrCosmos}, "iaa1ljemm0yznz58qxxs8xyak7fashcfxf5lgl4zjx", ""},
+		{"cosmos to val", []string{addrCosmos, "--to", "val"}, "iva1ljemm0yznz58qxxs8xyak7fashcfxf5lawld0p", ""},
+		{"cosmos to cons", []string{addrCosmos, "--to", "cons"}, "ica1ljemm0yznz58qxxs8xyak7fashcfxf5l5kg9dz", ""},
+		{"cosmos to hex", []string{addrCosmos, "--to", "hex"}, addrHex, ""},
+		{"hex to prefix", []string{addrHex, "--prefix", "osmo"}, "osmo1ljemm0yznz58qxxs8xyak7fashcfxf5l4xxrx9", ""},
+		{"0x hex to acc", []string{"0x" + strings.ToLower(addrHex)}, "iaa1ljemm0yznz58qxxs8xyak7fashcfxf5lgl4zjx", ""},
+		{
+			"32 bytes bech32 to hex",
+			[]string{"cosmos1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0sxaggsw", "--to", "hex"},
+			"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			"",
+		},
+		{"to and prefix", []string{addrCosmos, "--to", "val", "--prefix", "osmo"}, "", "--to and --prefix can not be used together"},
+		{"invalid format", []string{addrCosmos, "--to", "pub"}, "", "invalid format pub"},
+		{"invalid address", []string{"iaa1invalid"}, "", "invalid address iaa1invalid, must be bech32 or hex"},
+		{"short hex", []string{"FCB3BDBC"}, "", "invalid address FCB3BDBC: incorrect address length"},
+		{"21 bytes hex", []string{addrHex + "00"}, "", "incorrect address length"},
+		{"empty hex", []string{"0x"}, "", "invalid address 0x: incorrect address length"},
+	}
+
+	for _, tc := range tests {
+		out, err := runCommand(addrConvertCmd(), nil, "", tc.args...)
+		if tc.err != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected+"\n", out, tc.name)
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
+
+	"github.com/irisnet/irishub/app"
+)
+
+const (
+	flagModules        = "modules"
+	flagExcludeModules = "exclude-modules"
+	flagChunkSize      = "chunk-size"
+
+	// exportGenesisFile is the file under the output dir holding the genesis doc without the app state
+	exportGenesisFile = "genesis.json"
+)
+
+// replaceExportCmd replaces the export command added by the server with the given one
+func replaceExportCmd(rootCmd *cobra.Command, exportCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == exportCmd.Name() {
+			rootCmd.RemoveCommand(cmd)
+		}
+	}
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportCmd dumps the app state of all or some of the modules to JSON, either to STDOUT or to a directory
+func exportCmd(defaultNodeHome string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export state to JSON",
+		Long: fmt.Sprintf(`Export the state of the modules to a genesis file printed to STDOUT. The state of a subset
+of the modules can be exported with --%s and --%s.
+
+With --%s, the state of each module is written to <module>.json under the directory as soon as
+it is exported instead, along with the genesis doc to %s, whose app state only holds the hash
+of the directory. The elements of the arrays of a module state longer than --%s are written in
+chunks to <module>.<path>.<n>.json, which bounds the size of the files; the state of a module
+is still exported and initialized as a whole. The chain can be initialized from the directory
+by starting the node with the genesis doc and --%s, which checks the hash of the directory.
+
+With --%s, the state is exported at a past height, which must not have been pruned from the
+application state by the node, as per the pruning options of app.toml.`,
+			flagModules, flagExcludeModules, flagOutputDir, exportGenesisFile, flagChunkSize, app.FlagGenesisDir, server.FlagHeight,
+		),
+		Example: fmt.Sprintf("$ %s export --modules bank,staking,coinswap --%s ./export", version.AppName, flagOutputDir),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+
+			homeDir, _ := cmd.Flags().GetString(flags.FlagHome)
+			config.SetRoot(homeDir)
+
+			if _, err := os.Stat(config.GenesisFile()); os.IsNotExist(err) {
+				return err
+			}
+
+			db, err := sdk.NewLevelDB("application", filepath.Join(config.RootDir, "data"))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			height, _ := cmd.Flags().GetInt64(server.FlagHeight)
+			forZeroHeight, _ := cmd.Flags().GetBool(server.FlagForZeroHeight)
+			jailAllowedAddrs, _ := cmd.Flags().GetStringSlice(server.FlagJailAllowedAddrs)
+			modules, _ := cmd.Flags().GetStringSlice(flagModules)
+			excludeModules, _ := cmd.Flags().GetStringSlice(flagExcludeModules)
+			outputDir, _ := cmd.Flags().GetString(flagOutputDir)
+			chunkSize, _ := cmd.Flags().GetInt(flagChunkSize)
+
+			irisApp, err := newExportApp(serverCtx.Logger, db, nil, height, serverCtx.Viper)
+			if err != nil {
+				return fmt.Errorf("error exporting state: %v", err)
+			}
+			if modules, err = irisApp.ExportModuleNames(modules, excludeModules); err != nil {
+				return err
+			}
+
+			doc, err := tmtypes.GenesisDocFromFile(config.GenesisFile())
+			if err != nil {
+				return err
+			}
+
+			if outputDir == "" {
+				exported, err := irisApp.ExportAppStateAndValidatorsOfModules(forZeroHeight, jailAllowedAddrs, modules)
+				if err != nil {
+					return fmt.Errorf("error exporting state: %v", err)
+				}
+				setExportedGenesisDoc(doc, exported)
+
+				encoded, err := tmjson.Marshal(doc)
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(sdk.MustSortJSON(encoded)))
+				return nil
+			}
+
+			writer, err := app.NewChunkedGenesisWriter(outputDir, chunkSize)
+			if err != nil {
+				return err
+			}
+			exported, err := irisApp.ExportModuleStates(forZeroHeight, jailAllowedAddrs, modules, writer.WriteModule)
+			if err != nil {
+				return fmt.Errorf("error exporting state: %v", err)
+			}
+			// the app state only identifies the genesis directory the chain is initialized from
+			hash, err := irisApp.HashGenesisDir(outputDir)
+			if err != nil {
+				return err
+			}
+			if exported.AppState, err = json.Marshal(map[string]string{app.GenesisDirHashKey: hash}); err != nil {
+				return err
+			}
+			setExportedGenesisDoc(doc, exported)
+			if err := doc.SaveAs(filepath.Join(outputDir, exportGenesisFile)); err != nil {
+				return err
+			}
+
+			cmd.PrintErrf("Exported %d modules to %s\n", len(modules), outputDir)
+			return nil
+		},
+	}
+	cmd.SetOut(cmd.OutOrStdout())
+	cmd.SetErr(cmd.ErrOrStderr())
+	cmd.Flags().String(flags.FlagHome, defaultNodeHome, "The application home directory")
+	cmd.Flags().Int64(server.FlagHeight, -1, "Export state from a particular height (-1 means latest height)")
+	cmd.Flags().Bool(server.FlagForZeroHeight, false, "Export state to start at height zero (perform preproccessing)")
+	cmd.Flags().StringSlice(server.FlagJailAllowedAddrs, []string{}, "Comma-separated list of operator addresses of jailed validators to unjail")
+	cmd.Flags().StringSlice(flagModules, []string{}, "Comma-separated list of the modules to export (default all)")
+	cmd.Flags().StringSlice(flagExcludeModules, []string{}, "Comma-separated list of the modules not to export")
+	cmd.Flags().String(flagOutputDir, "", "Directory to write the state of each module to a separate file into")
+	cmd.Flags().Int(flagChunkSize, app.DefaultGenesisChunkSize, "Max number of the elements of an array in a chunk file of the output dir (0 to disable chunking)")
+
+	return cmd
+}
+
+// setExportedGenesisDoc sets the exported app state, validators, height and consensus params to the genesis doc
+func setExportedGenesisDoc(doc *tmtypes.GenesisDoc, exported servertypes.ExportedApp) {
+	doc.AppState = exported.AppState
+	doc.Validators = exported.Validators
+	doc.InitialHeight = exported.Height
+	doc.ConsensusParams = &tmproto.ConsensusParams{
+		Block: tmproto.BlockParams{
+			MaxBytes:   exported.ConsensusParams.Block.MaxBytes,
+			MaxGas:     exported.ConsensusParams.Block.MaxGas,
+			TimeIotaMs: doc.ConsensusParams.Block.TimeIotaMs,
+		},
+		Evidence: tmproto.EvidenceParams{
+			MaxAgeNumBlocks: exported.ConsensusParams.Evidence.MaxAgeNumBlocks,
+			MaxAgeDuration:  exported.ConsensusParams.Evidence.MaxAgeDuration,
+			MaxBytes:        exported.ConsensusParams.Evidence.MaxBytes,
+		},
+		Validator: tmproto.ValidatorParams{
+			PubKeyTypes: exported.ConsensusParams.Validator.PubKeyTypes,
+		},
+	}
+}
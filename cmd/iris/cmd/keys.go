@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 
 	"github.com/tendermint/tendermint/libs/cli"
 
@@ -13,6 +19,9 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 
 	"github.com/irisnet/irishub/keystore"
 )
@@ -52,6 +61,9 @@ The pass backend requires GnuPG: https://gnupg.org/
 		keys.AddKeyCommand(),
 		keys.ExportKeyCommand(),
 		importKeyCommand(),
+		importBatchCommand(),
+		exportKeystoreCommand(),
+		inspectKeystoreCommand(),
 		keys.ListKeysCmd(),
 		keys.ShowKeysCmd(),
 		flags.LineBreak,
@@ -69,7 +81,7 @@ The pass backend requires GnuPG: https://gnupg.org/
 }
 
 func importKeyCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "import <name> <keyfile>",
 		Short: "Import private keys into the local keybase",
 		Long:  "Import a ASCII armored private key into the local keybase.",
@@ -91,18 +103,227 @@ func importKeyCommand() *cobra.Command {
 				return err
 			}
 
-			armor, err := getArmor(bz, passphrase)
+			keyType, _ := cmd.Flags().GetString(flagKeyType)
+			armor, err := getArmor(bz, passphrase, keyType)
 			if err != nil {
 				return err
 			}
 			return clientCtx.Keyring.ImportPrivKey(args[0], armor, passphrase)
 		},
 	}
+
+	cmd.Flags().String(flagKeyType, "", fmt.Sprintf("Key type of the legacy keystore file if it does not carry one (%s|%s|%s)", keystore.KeyTypeSecp256k1, keystore.KeyTypeEd25519, keystore.KeyTypeSM2))
+	return cmd
+}
+
+// flags of the keystore commands
+const (
+	flagKDF        = "kdf"
+	flagOutputFile = "output-file"
+	flagKeyType    = "key-type"
+
+	flagCheckPassphrase = "check-passphrase"
+)
+
+func exportKeystoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-keystore <name>",
+		Short: "Export a private key to an encrypted keystore file",
+		Long: `Export a private key from the local keybase to a keystore file (EncryptedKeyJSON),
+encrypted with AES-128-CTR and a key derived from the passphrase by PBKDF2 or scrypt.
+The keystore file can be read by the wallets and custody tools supporting the legacy format.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buf := bufio.NewReader(cmd.InOrStdin())
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			kdf, _ := cmd.Flags().GetString(flagKDF)
+			if kdf != keystore.KDFPBKDF2 && kdf != keystore.KDFScrypt {
+				return fmt.Errorf("invalid kdf %s, must be %s or %s", kdf, keystore.KDFPBKDF2, keystore.KDFScrypt)
+			}
+
+			// a mistyped passphrase would leave a keystore nobody can decrypt
+			passphrase, err := getCheckPassword("Enter passphrase to encrypt the exported keystore:", "Repeat the passphrase:", buf)
+			if err != nil {
+				return err
+			}
+
+			armor, err := clientCtx.Keyring.ExportPrivKeyArmor(args[0], passphrase)
+			if err != nil {
+				return err
+			}
+			privKey, _, err := crypto.UnarmorDecryptPrivKey(armor, passphrase)
+			if err != nil {
+				return err
+			}
+
+			bz, err := keystore.ExportKeyStore(privKey, passphrase, kdf)
+			if err != nil {
+				return err
+			}
+
+			outputFile, _ := cmd.Flags().GetString(flagOutputFile)
+			if outputFile == "" {
+				outputFile = args[0] + ".json"
+			}
+			if err := ioutil.WriteFile(outputFile, bz, 0600); err != nil {
+				return err
+			}
+
+			cmd.PrintErrf("Keystore of %s exported to %s\n", args[0], outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagKDF, keystore.KDFPBKDF2, fmt.Sprintf("Key derivation function (%s|%s)", keystore.KDFPBKDF2, keystore.KDFScrypt))
+	cmd.Flags().String(flagOutputFile, "", "The keystore file to write, defaults to <name>.json")
+	return cmd
+}
+
+// keystoreInspection defines the inspection result of a keystore file
+type keystoreInspection struct {
+	Version        string                 `json:"version" yaml:"version"`
+	ID             string                 `json:"id" yaml:"id"`
+	Address        string                 `json:"address" yaml:"address"`
+	KeyType        string                 `json:"key_type,omitempty" yaml:"key_type,omitempty"`
+	Cipher         string                 `json:"cipher" yaml:"cipher"`
+	KDF            string                 `json:"kdf" yaml:"kdf"`
+	KDFParams      map[string]interface{} `json:"kdf_params" yaml:"kdf_params"`
+	MACValid       *bool                  `json:"mac_valid,omitempty" yaml:"mac_valid,omitempty"`
+	DerivedAddress string                 `json:"derived_address,omitempty" yaml:"derived_address,omitempty"`
+	AddressMatch   *bool                  `json:"address_match,omitempty" yaml:"address_match,omitempty"`
+	Error          string                 `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func inspectKeystoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect-keystore <file>",
+		Short: "Inspect a legacy keystore file without importing it",
+		Long: `Print the cipher, KDF parameters, version and address of a keystore file (EncryptedKeyJSON).
+
+With --check-passphrase the passphrase is checked against the MAC of the keystore, and the address
+derived from the decrypted key is compared with the address stored in the keystore. The addresses
+are compared by their bytes, so a stored address with a legacy bech32 prefix still matches.
+The local keybase is not accessed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			var encryptedKey keystore.EncryptedKeyJSON
+			if err := json.Unmarshal(bz, &encryptedKey); err != nil {
+				return fmt.Errorf("invalid keystore file %s: %s", args[0], err)
+			}
+
+			inspection := keystoreInspection{
+				Version:   string(encryptedKey.Version),
+				ID:        encryptedKey.ID,
+				Address:   encryptedKey.Address,
+				KeyType:   encryptedKey.KeyType,
+				Cipher:    encryptedKey.Crypto.Cipher,
+				KDF:       encryptedKey.Crypto.KDF,
+				KDFParams: encryptedKey.Crypto.KDFParams,
+			}
+
+			if checkPassphrase, _ := cmd.Flags().GetBool(flagCheckPassphrase); checkPassphrase {
+				buf := bufio.NewReader(cmd.InOrStdin())
+				passphrase, err := input.GetPassword("Enter passphrase to decrypt the keystore:", buf)
+				if err != nil {
+					return err
+				}
+				keyType, _ := cmd.Flags().GetString(flagKeyType)
+				inspectKeystoreKey(&inspection, bz, passphrase, keyType)
+			}
+
+			output, _ := cmd.Flags().GetString(cli.OutputFlag)
+			if output == keys.OutputFormatJSON {
+				out, err := json.MarshalIndent(inspection, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(out))
+				return nil
+			}
+
+			out, err := yaml.Marshal(inspection)
+			if err != nil {
+				return err
+			}
+			cmd.Print(string(out))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagCheckPassphrase, false, "Check the passphrase against the MAC and compare the derived address with the stored one")
+	cmd.Flags().String(flagKeyType, "", fmt.Sprintf("Key type of the legacy keystore file if it does not carry one (%s|%s|%s)", keystore.KeyTypeSecp256k1, keystore.KeyTypeEd25519, keystore.KeyTypeSM2))
+	return cmd
+}
+
+// inspectKeystoreKey decrypts the keystore and records the MAC check and the derived address;
+// the MAC is reported valid even if the decrypted key bytes do not make a key of the key type
+func inspectKeystoreKey(inspection *keystoreInspection, bz []byte, passphrase, keyType string) {
+	keyBytes, keyType, err := keystore.DecryptKeyStoreBytes(bz, passphrase, keyType)
+	if err == keystore.ErrDecrypt {
+		macValid := false
+		inspection.MACValid = &macValid
+		return
+	}
+	if err != nil {
+		inspection.Error = err.Error()
+		return
+	}
+
+	macValid := true
+	inspection.MACValid = &macValid
+
+	privKey, err := keystore.PrivKeyFromBytes(keyBytes, keyType)
+	if err != nil {
+		inspection.Error = err.Error()
+		return
+	}
+
+	derived := sdk.AccAddress(privKey.PubKey().Address())
+	inspection.DerivedAddress = derived.String()
+
+	if inspection.Address == "" {
+		return
+	}
+	_, stored, err := bech32.DecodeAndConvert(inspection.Address)
+	if err != nil {
+		// the address may also be stored in hex
+		if stored, err = hex.DecodeString(strings.TrimPrefix(inspection.Address, "0x")); err != nil {
+			inspection.Error = fmt.Sprintf("invalid stored address %s", inspection.Address)
+			return
+		}
+	}
+	addressMatch := bytes.Equal(stored, derived)
+	inspection.AddressMatch = &addressMatch
+}
+
+// getCheckPassword prompts for a password twice and checks that both are the same
+func getCheckPassword(prompt, repeatPrompt string, buf *bufio.Reader) (string, error) {
+	pass, err := input.GetPassword(prompt, buf)
+	if err != nil {
+		return "", err
+	}
+	repeat, err := input.GetPassword(repeatPrompt, buf)
+	if err != nil {
+		return "", err
+	}
+	if pass != repeat {
+		return "", errors.New("passphrases don't match")
+	}
+	return pass, nil
 }
 
-func getArmor(privBytes []byte, passphrase string) (string, error) {
+func getArmor(privBytes []byte, passphrase, keyType string) (string, error) {
 	if !json.Valid(privBytes) {
 		return string(privBytes), nil
 	}
-	return keystore.RecoveryAndExportPrivKeyArmor(privBytes, passphrase)
+	return keystore.RecoveryAndExportPrivKeyArmorWithType(privBytes, passphrase, keyType)
 }
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/input"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+// flags of the import-batch command
+const (
+	flagManifest       = "manifest"
+	flagPassphraseEnv  = "passphrase-env"
+	flagPassphraseFile = "passphrase-file"
+	flagDryRun         = "dry-run"
+)
+
+// statuses of the keys in the batch import report
+const (
+	importStatusImported = "imported"
+	importStatusVerified = "verified"
+	importStatusFailed   = "failed"
+)
+
+// importEntry defines a key to be imported by the batch import
+type importEntry struct {
+	Name           string `json:"name"`
+	File           string `json:"file"`
+	PassphraseEnv  string `json:"passphrase_env,omitempty"`
+	PassphraseFile string `json:"passphrase_file,omitempty"`
+	KeyType        string `json:"key_type,omitempty"`
+}
+
+// importResult defines the result of importing a key
+type importResult struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func importBatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-batch [dir]",
+		Short: "Import many private keys into the local keybase in one run",
+		Long: `Import the ASCII armored private keys or legacy keystore files into the local keybase.
+
+The keys are read either from a directory, where each file is imported under its name without
+the extension, or from a JSON manifest listing the keys:
+
+[
+  {"name": "alice", "file": "alice.json", "passphrase_env": "ALICE_PASSPHRASE"},
+  {"name": "bob", "file": "bob.json", "passphrase_file": "/path/to/bob.pass", "key_type": "ed25519"}
+]
+
+The relative paths in the manifest are resolved against the directory of the manifest. A key
+without a passphrase source uses the one given by --passphrase-env or --passphrase-file, and
+the passphrase is prompted once if none is given. Likewise a key without a key type uses --key-type.
+
+With --dry-run the keys are only decrypted and nothing is written to the keybase.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buf := bufio.NewReader(cmd.InOrStdin())
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			manifest, _ := cmd.Flags().GetString(flagManifest)
+			var entries []importEntry
+			switch {
+			case len(args) == 1 && manifest == "":
+				entries, err = readImportDir(args[0])
+			case len(args) == 0 && manifest != "":
+				entries, err = readImportManifest(manifest)
+			default:
+				return fmt.Errorf("either a directory or --%s must be given", flagManifest)
+			}
+			if err != nil {
+				return err
+			}
+			if err := checkImportNames(entries); err != nil {
+				return err
+			}
+
+			defaultEntry := importEntry{}
+			defaultEntry.PassphraseEnv, _ = cmd.Flags().GetString(flagPassphraseEnv)
+			defaultEntry.PassphraseFile, _ = cmd.Flags().GetString(flagPassphraseFile)
+			defaultEntry.KeyType, _ = cmd.Flags().GetString(flagKeyType)
+			dryRun, _ := cmd.Flags().GetBool(flagDryRun)
+
+			var prompted *string
+			results := make([]importResult, len(entries))
+			for i, entry := range entries {
+				results[i] = importResult{Name: entry.Name, File: entry.File, Status: importStatusFailed}
+
+				if entry.PassphraseEnv == "" && entry.PassphraseFile == "" {
+					entry.PassphraseEnv, entry.PassphraseFile = defaultEntry.PassphraseEnv, defaultEntry.PassphraseFile
+				}
+				if entry.KeyType == "" {
+					entry.KeyType = defaultEntry.KeyType
+				}
+
+				var passphrase string
+				if entry.PassphraseEnv == "" && entry.PassphraseFile == "" {
+					if prompted == nil {
+						p, err := input.GetPassword("Enter passphrase to decrypt your keys:", buf)
+						if err != nil {
+							return err
+						}
+						prompted = &p
+					}
+					passphrase = *prompted
+				} else if passphrase, err = entry.passphrase(); err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+
+				if err := importKey(clientCtx.Keyring, entry, passphrase, dryRun); err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+
+				results[i].Status = importStatusImported
+				if dryRun {
+					results[i].Status = importStatusVerified
+				}
+			}
+
+			output, _ := cmd.Flags().GetString(cli.OutputFlag)
+			if err := printImportResults(cmd, results, output); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, result := range results {
+				if result.Status == importStatusFailed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d of %d keys failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagManifest, "", "The JSON manifest listing the keys to import")
+	cmd.Flags().String(flagPassphraseEnv, "", "The environment variable holding the passphrase of the keys without their own passphrase source")
+	cmd.Flags().String(flagPassphraseFile, "", "The file holding the passphrase of the keys without their own passphrase source")
+	cmd.Flags().String(flagKeyType, "", "Key type of the legacy keystore files without their own key type")
+	cmd.Flags().Bool(flagDryRun, false, "Only verify that the keys can be decrypted, without importing them")
+	return cmd
+}
+
+// passphrase returns the passphrase of the key from its passphrase source
+func (e importEntry) passphrase() (string, error) {
+	if e.PassphraseEnv != "" && e.PassphraseFile != "" {
+		return "", fmt.Errorf("only one of passphrase_env and passphrase_file can be set")
+	}
+
+	if e.PassphraseEnv != "" {
+		passphrase, ok := os.LookupEnv(e.PassphraseEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", e.PassphraseEnv)
+		}
+		return passphrase, nil
+	}
+
+	bz, err := ioutil.ReadFile(e.PassphraseFile)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(bz), "\r\n"), nil
+}
+
+// importKey imports the key of the entry, or only decrypts it if dryRun is set
+func importKey(kr keyring.Keyring, entry importEntry, passphrase string, dryRun bool) error {
+	if _, err := kr.Key(entry.Name); err == nil {
+		return fmt.Errorf("key %s already exists", entry.Name)
+	}
+
+	bz, err := ioutil.ReadFile(entry.File)
+	if err != nil {
+		return err
+	}
+
+	armor, err := getArmor(bz, passphrase, entry.KeyType)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		_, _, err := crypto.UnarmorDecryptPrivKey(armor, passphrase)
+		return err
+	}
+	return kr.ImportPrivKey(entry.Name, armor, passphrase)
+}
+
+// readImportDir returns the entries of the files in the directory, named after the files
+func readImportDir(dir string) ([]importEntry, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []importEntry
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		entries = append(entries, importEntry{
+			Name: strings.TrimSuffix(f.Name(), filepath.Ext(f.Name())),
+			File: filepath.Join(dir, f.Name()),
+		})
+	}
+	return entries, nil
+}
+
+// readImportManifest returns the entries listed in the manifest
+func readImportManifest(manifest string) ([]importEntry, error) {
+	bz, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []importEntry
+	if err := json.Unmarshal(bz, &entries); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %s", manifest, err)
+	}
+
+	dir := filepath.Dir(manifest)
+	for i, entry := range entries {
+		if entry.Name == "" || entry.File == "" {
+			return nil, fmt.Errorf("invalid manifest %s: name and file are required, got %+v", manifest, entry)
+		}
+		if !filepath.IsAbs(entry.File) {
+			entries[i].File = filepath.Join(dir, entry.File)
+		}
+		if entry.PassphraseFile != "" && !filepath.IsAbs(entry.PassphraseFile) {
+			entries[i].PassphraseFile = filepath.Join(dir, entry.PassphraseFile)
+		}
+	}
+	return entries, nil
+}
+
+// checkImportNames returns an error if several entries import a key under the same name,
+// so that the duplicates are reported before any key is imported
+func checkImportNames(entries []importEntry) error {
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if file, ok := files[entry.Name]; ok {
+			return fmt.Errorf("duplicate key name %s of %s and %s", entry.Name, file, entry.File)
+		}
+		files[entry.Name] = entry.File
+	}
+	return nil
+}
+
+func printImportResults(cmd *cobra.Command, results []importResult, output string) error {
+	if output == keys.OutputFormatJSON {
+		bz, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFILE\tSTATUS\tERROR")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.File, r.Status, r.Error)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	file := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func TestReadImportManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected []importEntry
+		errMsg   string
+	}{
+		{
+			"relative and absolute paths",
+			`[{"name":"alice","file":"alice.json","passphrase_file":"alice.pass"},{"name":"bob","file":"/keys/bob.json","passphrase_env":"BOB","key_type":"ed25519"}]`,
+			[]importEntry{
+				{Name: "alice", File: filepath.Join(dir, "alice.json"), PassphraseFile: filepath.Join(dir, "alice.pass")},
+				{Name: "bob", File: "/keys/bob.json", PassphraseEnv: "BOB", KeyType: "ed25519"},
+			},
+			"",
+		},
+		{"missing name", `[{"file":"alice.json"}]`, nil, "name and file are required"},
+		{"missing file", `[{"name":"alice"}]`, nil, "name and file are required"},
+		{"not a list", `{"name":"alice","file":"alice.json"}`, nil, "invalid manifest"},
+	}
+
+	for _, tc := range tests {
+		entries, err := readImportManifest(writeTestFile(t, dir, "manifest.json", tc.manifest))
+		if tc.errMsg != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, entries, tc.name)
+	}
+}
+
+func TestCheckImportNames(t *testing.T) {
+	require.NoError(t, checkImportNames([]importEntry{{Name: "alice", File: "a"}, {Name: "bob", File: "b"}}))
+
+	err := checkImportNames([]importEntry{{Name: "alice", File: "a.json"}, {Name: "bob", File: "b"}, {Name: "alice", File: "a.armor"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate key name alice of a.json and a.armor")
+
+	// files of the same name with different extensions in an import directory
+	dir := t.TempDir()
+	writeTestFile(t, dir, "alice.json", "{}")
+	writeTestFile(t, dir, "alice.armor", "")
+	entries, err := readImportDir(dir)
+	require.NoError(t, err)
+	require.Error(t, checkImportNames(entries))
+}
+
+func TestImportEntryPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	const env = "IRIS_TEST_IMPORT_PASSPHRASE"
+	os.Setenv(env, "from env")
+	defer os.Unsetenv(env)
+
+	tests := []struct {
+		name     string
+		entry    importEntry
+		expected string
+		errMsg   string
+	}{
+		{"env", importEntry{PassphraseEnv: env}, "from env", ""},
+		{"unset env", importEntry{PassphraseEnv: env + "_UNSET"}, "", "is not set"},
+		{"file", importEntry{PassphraseFile: writeTestFile(t, dir, "lf.pass", "from file\n")}, "from file", ""},
+		{"file with crlf", importEntry{PassphraseFile: writeTestFile(t, dir, "crlf.pass", "from file\r\n")}, "from file", ""},
+		{"file keeping spaces", importEntry{PassphraseFile: writeTestFile(t, dir, "spaces.pass", " from file \n")}, " from file ", ""},
+		{"missing file", importEntry{PassphraseFile: filepath.Join(dir, "missing.pass")}, "", "no such file"},
+		{"both sources", importEntry{PassphraseEnv: env, PassphraseFile: filepath.Join(dir, "lf.pass")}, "", "only one of"},
+	}
+
+	for _, tc := range tests {
+		passphrase, err := tc.entry.passphrase()
+		if tc.errMsg != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, passphrase, tc.name)
+	}
+}
+
+func TestImportBatchCommand(t *testing.T) {
+	dir := t.TempDir()
+	const passphrase = "12345678"
+	writeTestFile(t, dir, "pass", passphrase)
+	writeTestFile(t, dir, "alice.armor", crypto.EncryptArmorPrivKey(secp256k1.GenPrivKey(), passphrase, string(hd.Secp256k1Type)))
+	writeTestFile(t, dir, "bob.armor", crypto.EncryptArmorPrivKey(secp256k1.GenPrivKey(), passphrase, string(hd.Secp256k1Type)))
+	writeTestFile(t, dir, "carol.armor", crypto.EncryptArmorPrivKey(secp256k1.GenPrivKey(), "wrong passphrase", string(hd.Secp256k1Type)))
+
+	run := func(kr keyring.Keyring, manifest string, extraArgs ...string) ([]importResult, error) {
+		cmd := importBatchCommand()
+		cmd.Flags().String(cli.OutputFlag, keys.OutputFormatText, "")
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(ioutil.Discard)
+		cmd.SetArgs(append([]string{
+			"--" + flagManifest, writeTestFile(t, dir, "manifest.json", manifest),
+			"--" + flagPassphraseFile, filepath.Join(dir, "pass"),
+			"--" + cli.OutputFlag, keys.OutputFormatJSON,
+		}, extraArgs...))
+		err := cmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &client.Context{Keyring: kr}))
+
+		var results []importResult
+		if out.Len() > 0 {
+			require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+		}
+		return results, err
+	}
+
+	// duplicate names are rejected before any key is imported
+	kr := keyring.NewInMemory()
+	_, err := run(kr, `[{"name":"alice","file":"alice.armor"},{"name":"alice","file":"bob.armor"}]`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate key name alice")
+	infos, err := kr.List()
+	require.NoError(t, err)
+	require.Empty(t, infos)
+
+	manifest := `[{"name":"alice","file":"alice.armor"},{"name":"bob","file":"bob.armor"},{"name":"carol","file":"carol.armor"},{"name":"dave","file":"dave.armor"}]`
+	expected := []importResult{
+		{Name: "alice", File: filepath.Join(dir, "alice.armor"), Status: importStatusVerified},
+		{Name: "bob", File: filepath.Join(dir, "bob.armor"), Status: importStatusVerified},
+		{Name: "carol", File: filepath.Join(dir, "carol.armor"), Status: importStatusFailed},
+		{Name: "dave", File: filepath.Join(dir, "dave.armor"), Status: importStatusFailed},
+	}
+
+	// the dry run reports the keys without importing them
+	results, err := run(kr, manifest, "--"+flagDryRun)
+	require.EqualError(t, err, "2 of 4 keys failed")
+	for i := range results {
+		require.Equal(t, expected[i].Status, results[i].Status, results[i].Name)
+		results[i].Error = ""
+	}
+	require.Equal(t, expected, results)
+	infos, err = kr.List()
+	require.NoError(t, err)
+	require.Empty(t, infos)
+
+	results, err = run(kr, manifest)
+	require.EqualError(t, err, "2 of 4 keys failed")
+	require.Equal(t, importStatusImported, results[0].Status)
+	require.Equal(t, importStatusImported, results[1].Status)
+	require.NotEmpty(t, results[2].Error)
+	require.NotEmpty(t, results[3].Error)
+	infos, err = kr.List()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+
+	// the imported keys are reported as existing
+	results, err = run(kr, `[{"name":"alice","file":"alice.armor"}]`)
+	require.Error(t, err)
+	require.Equal(t, "key alice already exists", results[0].Error)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/keystore"
+)
+
+// runCommand runs the command with the client keyring, reading the input from in
+func runCommand(cmd *cobra.Command, kr keyring.Keyring, in string, args ...string) (string, error) {
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	out := &strings.Builder{}
+	cmd.SetOut(out)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetIn(strings.NewReader(in))
+	cmd.SetArgs(args)
+	err := cmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &client.Context{Keyring: kr}))
+	return out.String(), err
+}
+
+func TestExportKeystoreCommand(t *testing.T) {
+	dir := t.TempDir()
+	const passphrase = "12345678"
+
+	privKey := secp256k1.GenPrivKey()
+	kr := keyring.NewInMemory()
+	require.NoError(t, kr.ImportPrivKey("alice", crypto.EncryptArmorPrivKey(privKey, passphrase, string(hd.Secp256k1Type)), passphrase))
+
+	// the passphrase is asked twice, and a mistyped one is rejected
+	file := filepath.Join(dir, "mistyped.json")
+	_, err := runCommand(exportKeystoreCommand(), kr, passphrase+"\n"+"12345679\n", "alice", "--"+flagOutputFile, file)
+	require.EqualError(t, err, "passphrases don't match")
+	_, err = os.Stat(file)
+	require.True(t, os.IsNotExist(err))
+
+	file = filepath.Join(dir, "alice.json")
+	_, err = runCommand(exportKeystoreCommand(), kr, passphrase+"\n"+passphrase+"\n", "alice", "--"+flagOutputFile, file)
+	require.NoError(t, err)
+	bz, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	decrypted, err := keystore.DecryptKeyStore(bz, passphrase, "")
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(decrypted))
+}
+
+func TestInspectKeystoreCommand(t *testing.T) {
+	dir := t.TempDir()
+	const passphrase = "12345678"
+
+	inspect := func(file, passphrase string) keystoreInspection {
+		out, err := runCommand(inspectKeystoreCommand(), nil, passphrase+"\n", file, "--"+flagCheckPassphrase)
+		require.NoError(t, err)
+		var inspection keystoreInspection
+		require.NoError(t, yaml.Unmarshal([]byte(out), &inspection))
+		return inspection
+	}
+
+	privKey := secp256k1.GenPrivKey()
+	bz, err := keystore.ExportKeyStore(privKey, passphrase, keystore.KDFScrypt)
+	require.NoError(t, err)
+	file := writeTestFile(t, dir, "alice.json", string(bz))
+
+	inspection := inspect(file, passphrase)
+	require.True(t, *inspection.MACValid)
+	require.Equal(t, sdk.AccAddress(privKey.PubKey().Address()).String(), inspection.DerivedAddress)
+	require.True(t, *inspection.AddressMatch)
+	require.Empty(t, inspection.Error)
+
+	inspection = inspect(file, "12345679")
+	require.False(t, *inspection.MACValid)
+	require.Empty(t, inspection.DerivedAddress)
+	require.Nil(t, inspection.AddressMatch)
+
+	// the stored address does not match the decrypted key
+	var encryptedKey map[string]interface{}
+	require.NoError(t, json.Unmarshal(bz, &encryptedKey))
+	encryptedKey["address"] = sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+	mismatched, err := json.Marshal(encryptedKey)
+	require.NoError(t, err)
+
+	inspection = inspect(writeTestFile(t, dir, "mismatched.json", string(mismatched)), passphrase)
+	require.True(t, *inspection.MACValid)
+	require.Equal(t, sdk.AccAddress(privKey.PubKey().Address()).String(), inspection.DerivedAddress)
+	require.False(t, *inspection.AddressMatch)
+
+	// the MAC is valid even if the key bytes do not make a key of the key type:
+	// a 64 bytes ed25519 key without its key type is taken as a secp256k1 key
+	bz, err = keystore.ExportKeyStore(ed25519.GenPrivKey(), passphrase, keystore.KDFScrypt)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(bz, &encryptedKey))
+	delete(encryptedKey, "key_type")
+	untyped, err := json.Marshal(encryptedKey)
+	require.NoError(t, err)
+
+	inspection = inspect(writeTestFile(t, dir, "untyped.json", string(untyped)), passphrase)
+	require.True(t, *inspection.MACValid)
+	require.Empty(t, inspection.DerivedAddress)
+	require.NotEmpty(t, inspection.Error)
+}
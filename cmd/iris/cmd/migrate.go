@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
+
+	"github.com/irisnet/irishub/app"
+	"github.com/irisnet/irishub/migrate"
+	migratehtlc "github.com/irisnet/irishub/migrate/htlc"
+)
+
+const (
+	flagPlan        = "plan"
+	flagInfo        = "info"
+	flagGenesisTime = "genesis-time"
+)
+
+// migrateCmd returns the commands to run the migrations offline
+func migrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Run the state migrations offline",
+	}
+
+	cmd.AddCommand(
+		dryRunUpgradeCmd(),
+		migrateGenesisCmd(),
+	)
+	return cmd
+}
+
+func dryRunUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dry-run",
+		Short: "Run the migrations of an upgrade plan against a copy of the data directory",
+		Long: `Load the latest version of the application state under the home, run the migrations of the
+given upgrade plan in a cached context, then print a summary of the state changes and check all
+the crisis invariants. Nothing is written to the application state, which is still opened for
+writing by the database, so point --home at a copy of the node home rather than a live node.
+
+The migrations are given the upgrade plan scheduled on chain, whose info is the same on every node.
+If the plan is not scheduled yet, its info is taken from --info instead.`,
+		Example: fmt.Sprintf("$ %s migrate dry-run --plan v1.1 --home /path/to/copy", version.AppName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			home := serverCtx.Config.RootDir
+
+			planName, _ := cmd.Flags().GetString(flagPlan)
+			if planName == "" {
+				return fmt.Errorf("--%s is required", flagPlan)
+			}
+
+			dataDir := filepath.Join(home, "data")
+			if _, err := os.Stat(filepath.Join(dataDir, "application.db")); err != nil {
+				return fmt.Errorf("application state not found under %s: %s", home, err)
+			}
+			db, err := sdk.NewLevelDB("application", dataDir)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			encodingConfig := app.MakeEncodingConfig()
+			irisApp := app.NewIrisApp(
+				serverCtx.Logger, db, nil, true, map[int64]bool{}, home, 0,
+				encodingConfig, serverCtx.Viper,
+			)
+			info, _ := cmd.Flags().GetString(flagInfo)
+			result, err := irisApp.DryRunUpgrade(planName, info)
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString(cli.OutputFlag)
+			if err := printDryRunResult(cmd, result, output); err != nil {
+				return err
+			}
+
+			if result.Error != "" || len(result.BrokenInvariants) > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("dry run of upgrade plan %s failed", planName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flags.FlagHome, app.DefaultNodeHome, "The home of the copied node data")
+	cmd.Flags().String(flagPlan, "", "Name of the upgrade plan to run")
+	cmd.Flags().StringP(cli.OutputFlag, "o", "text", "Output format (text|json)")
+	cmd.Flags().String(flagInfo, "", "Info of the upgrade plan if it is not scheduled yet, e.g. JSON with the htlc_params installed by the htlc migration")
+	addMigrationFlags(cmd)
+	return cmd
+}
+
+func migrateGenesisCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "genesis [target-version] [genesis-file]",
+		Short: "Migrate a genesis file exported by a previous version to a target version",
+		Long: `Transform the module sections of the genesis file step by step with the genesis migrations of
+all the versions up to the target one, validate the result and print it to STDOUT. The sections
+already in the format of a version are left unchanged by its migration.`,
+		Example: fmt.Sprintf("$ %s migrate genesis v1.1 /path/to/genesis.json --chain-id=irishub-1 --genesis-time=2021-08-01T00:00:00Z", version.AppName),
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+			cdc := clientCtx.JSONMarshaler
+			target, genesisFile := args[0], args[1]
+
+			genDoc, err := tmtypes.GenesisDocFromFile(genesisFile)
+			if err != nil {
+				return err
+			}
+
+			var appState migrate.AppState
+			if err := json.Unmarshal(genDoc.AppState, &appState); err != nil {
+				return fmt.Errorf("failed to unmarshal the app state of %s: %s", genesisFile, err)
+			}
+
+			htlcParams := migratehtlc.PresetHTLTParams()
+			if file, _ := cmd.Flags().GetString(migratehtlc.FlagPresetParamsFile); file != "" {
+				if htlcParams, err = migratehtlc.LoadPresetHTLTParams(cdc, file); err != nil {
+					return err
+				}
+			}
+
+			reports, err := app.GenesisMigrations(cdc, htlcParams).Migrate(appState, target)
+			if err != nil {
+				return err
+			}
+			for _, report := range reports {
+				cmd.PrintErrf("Applied %s genesis migration %s/%s\n", report.Version, report.Module, report.Name)
+			}
+
+			if err := app.ModuleBasics.ValidateGenesis(cdc, clientCtx.TxConfig, appState); err != nil {
+				return fmt.Errorf("migrated genesis is invalid: %s", err)
+			}
+
+			if genDoc.AppState, err = json.Marshal(appState); err != nil {
+				return err
+			}
+			if genesisTime, _ := cmd.Flags().GetString(flagGenesisTime); genesisTime != "" {
+				if err := genDoc.GenesisTime.UnmarshalText([]byte(genesisTime)); err != nil {
+					return fmt.Errorf("invalid genesis time %s: %s", genesisTime, err)
+				}
+			}
+			if chainID, _ := cmd.Flags().GetString(flags.FlagChainID); chainID != "" {
+				genDoc.ChainID = chainID
+			}
+
+			bz, err := tmjson.Marshal(genDoc)
+			if err != nil {
+				return err
+			}
+			sortedBz, err := sdk.SortJSON(bz)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(sortedBz))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGenesisTime, "", "Override the genesis_time of the migrated genesis")
+	cmd.Flags().String(flags.FlagChainID, "", "Override the chain_id of the migrated genesis")
+	cmd.Flags().String(migratehtlc.FlagPresetParamsFile, "", "JSON file of the htlc params installed by the htlc migration, replacing the preset ones")
+	return cmd
+}
+
+// addMigrationFlags adds the flags of the options of the upgrade migrations, which do not
+// change the migrated state
+func addMigrationFlags(cmd *cobra.Command) {
+	cmd.Flags().String(migratehtlc.FlagReportFile, "", "File to write the JSON report of the htlc migration into")
+}
+
+func printDryRunResult(cmd *cobra.Command, result app.DryRunResult, output string) error {
+	if output == formatJSON {
+		bz, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(bz))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Upgrade plan %s at height %d\n\n", result.Plan, result.Height)
+
+	fmt.Fprintln(w, "STEP\tMODULE\tDURATION\tERROR")
+	for _, step := range result.Report.Steps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", step.Name, step.Module, step.Duration, step.Error)
+	}
+
+	fmt.Fprintln(w, "\nSTORE\tADDED\tUPDATED\tDELETED")
+	for _, c := range result.Changes {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", c.Store, c.Added, c.Updated, c.Deleted)
+	}
+
+	if len(result.BrokenInvariants) == 0 {
+		fmt.Fprintln(w, "\nAll invariants hold")
+	} else {
+		fmt.Fprintln(w, "\nBROKEN INVARIANT\tMESSAGE")
+		for _, inv := range result.BrokenInvariants {
+			fmt.Fprintf(w, "%s\t%q\n", inv.Route, inv.Message)
+		}
+	}
+	return w.Flush()
+}
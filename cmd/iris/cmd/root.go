@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -14,7 +15,6 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/debug"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/rpc"
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -22,6 +22,7 @@ import (
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
 	"github.com/cosmos/cosmos-sdk/snapshots"
 	"github.com/cosmos/cosmos-sdk/store"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authclient "github.com/cosmos/cosmos-sdk/x/auth/client"
 	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
@@ -30,6 +31,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/crisis"
 	genutilcli "github.com/cosmos/cosmos-sdk/x/genutil/client/cli"
 
+	"github.com/irisnet/irishub/address"
 	"github.com/irisnet/irishub/app"
 	"github.com/irisnet/irishub/app/params"
 )
@@ -55,14 +57,25 @@ func NewRootCmd() (*cobra.Command, params.EncodingConfig) {
 			if err := client.SetCmdClientContextHandler(initClientCtx, cmd); err != nil {
 				return err
 			}
-			converter.handlePreRun(cmd, args)
-			return server.InterceptConfigsPreRunHandler(cmd)
+			if err := server.InterceptConfigsPreRunHandler(cmd); err != nil {
+				return err
+			}
+			if err := configureBech32Prefix(cmd); err != nil {
+				return err
+			}
+			return converter.handlePreRun(cmd, args)
 		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
 			converter.handlePostRun(cmd)
 		},
 	}
 
+	rootCmd.PersistentFlags().String(address.FlagBech32PrefixRoot, "", fmt.Sprintf("The bech32 prefix root of the addresses, overriding the %s env var and the app config (default %q)", address.EnvBech32PrefixRoot, address.Bech32ChainPrefix))
+
+	rootCmd.PersistentFlags().Bool(flagConvertOffline, false, fmt.Sprintf("Convert the coins with the token cache only, which is implied by --%s and --%s", flags.FlagOffline, flags.FlagGenerateOnly))
+	rootCmd.PersistentFlags().Duration(flagTokenCacheTTL, defaultTokenCacheTTL, "Time after which the cached token metadata is refreshed from the node")
+	rootCmd.PersistentFlags().Bool(flagDisplayUnits, false, "Convert the coins in the JSON query output and in the tx events to the main units")
+
 	initRootCmd(rootCmd, encodingConfig)
 
 	return rootCmd, encodingConfig
@@ -72,17 +85,20 @@ func initRootCmd(rootCmd *cobra.Command, encodingConfig params.EncodingConfig) {
 	authclient.Codec = encodingConfig.Marshaler
 
 	rootCmd.AddCommand(
-		genutilcli.InitCmd(app.ModuleBasics, app.DefaultNodeHome),
+		initCmd(app.ModuleBasics, app.DefaultNodeHome),
 		genutilcli.CollectGenTxsCmd(banktypes.GenesisBalancesIterator{}, app.DefaultNodeHome),
 		genutilcli.GenTxCmd(app.ModuleBasics, encodingConfig.TxConfig, banktypes.GenesisBalancesIterator{}, app.DefaultNodeHome),
 		genutilcli.ValidateGenesisCmd(app.ModuleBasics),
 		AddGenesisAccountCmd(app.DefaultNodeHome),
 		tmcli.NewCompletionCmd(rootCmd, true),
 		testnetCmd(app.ModuleBasics, banktypes.GenesisBalancesIterator{}),
-		debug.Cmd(),
+		debugCmd(),
+		tokensCmd(),
+		migrateCmd(),
 	)
 
 	server.AddCommands(rootCmd, app.DefaultNodeHome, newApp, createIrisappAndExport, addModuleInitFlags)
+	replaceExportCmd(rootCmd, exportCmd(app.DefaultNodeHome))
 
 	// add keybase, auxiliary RPC, query, and tx child commands
 	rootCmd.AddCommand(
@@ -95,6 +111,8 @@ func initRootCmd(rootCmd *cobra.Command, encodingConfig params.EncodingConfig) {
 
 func addModuleInitFlags(rootCmd *cobra.Command) {
 	crisis.AddModuleInitFlags(rootCmd)
+	addMigrationFlags(rootCmd)
+	rootCmd.Flags().String(app.FlagGenesisDir, "", "Directory of the chunked genesis state exported with export --output-dir to init the chain from")
 }
 
 func queryCommand() *cobra.Command {
@@ -199,18 +217,49 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer, appOpts serverty
 func createIrisappAndExport(
 	logger log.Logger, db dbm.DB, traceStore io.Writer, height int64, forZeroHeight bool, jailAllowedAddrs []string,
 	appOpts servertypes.AppOptions) (servertypes.ExportedApp, error) {
+	irisApp, err := newExportApp(logger, db, traceStore, height, appOpts)
+	if err != nil {
+		return servertypes.ExportedApp{}, err
+	}
+	return irisApp.ExportAppStateAndValidators(forZeroHeight, jailAllowedAddrs)
+}
+
+// newExportApp creates a new irisapp to export the state of, optionally at a given height
+func newExportApp(
+	logger log.Logger, db dbm.DB, traceStore io.Writer, height int64, appOpts servertypes.AppOptions,
+) (*app.IrisApp, error) {
 	encCfg := app.MakeEncodingConfig() // Ideally, we would reuse the one created by NewRootCmd.
 	encCfg.Marshaler = codec.NewProtoCodec(encCfg.InterfaceRegistry)
-	var irisApp *app.IrisApp
-	if height != -1 {
-		irisApp = app.NewIrisApp(logger, db, traceStore, false, map[int64]bool{}, "", uint(1), encCfg, appOpts)
-
-		if err := irisApp.LoadHeight(height); err != nil {
-			return servertypes.ExportedApp{}, err
-		}
-	} else {
-		irisApp = app.NewIrisApp(logger, db, traceStore, true, map[int64]bool{}, "", uint(1), encCfg, appOpts)
+	if height == -1 {
+		return app.NewIrisApp(logger, db, traceStore, true, map[int64]bool{}, "", uint(1), encCfg, appOpts), nil
+	}
+	if height <= 0 {
+		return nil, fmt.Errorf("invalid height %d, which must be positive or -1 for the latest height", height)
 	}
 
-	return irisApp.ExportAppStateAndValidators(forZeroHeight, jailAllowedAddrs)
+	latest, err := latestHeight(db)
+	if err != nil {
+		return nil, err
+	}
+	if height > latest {
+		return nil, fmt.Errorf("height %d is not committed yet, the latest height is %d", height, latest)
+	}
+
+	irisApp := app.NewIrisApp(logger, db, traceStore, false, map[int64]bool{}, "", uint(1), encCfg, appOpts)
+	if err := irisApp.LoadHeight(height); err != nil {
+		return nil, fmt.Errorf(
+			"height %d is not available in the application state, it has most likely been pruned as per the pruning options of app.toml (latest height %d): %w",
+			height, latest, err,
+		)
+	}
+	return irisApp, nil
+}
+
+// latestHeight returns the latest height committed to the application state, loading the commit info only
+func latestHeight(db dbm.DB) (int64, error) {
+	cms := rootmulti.NewStore(db)
+	if err := cms.LoadLatestVersion(); err != nil {
+		return 0, err
+	}
+	return cms.LastCommitID().Version, nil
 }
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
+
+	"github.com/irisnet/irishub/app"
+)
+
+const (
+	flagMaxChanges = "max-changes"
+	flagStores     = "stores"
+)
+
+func stateDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state-diff <a> <b>",
+		Short: "Compare two exported genesis files or two heights of the application state",
+		Long: fmt.Sprintf(`Compare the states a and b, which are either two genesis files exported by the export command,
+compared module by module with the elements of the arrays matched by their address, id or denom,
+or two heights of the application state under the home, compared store by store with the changed
+values decoded by the store decoders of the modules.
+
+At most --%s changes are listed per module or store, which can be filtered with --%s.`, flagMaxChanges, flagStores),
+		Example: fmt.Sprintf(`$ %[1]s debug state-diff export-a.json export-b.json
+$ %[1]s debug state-diff 1000 1001 --home /path/to/copy --stores htlc -o json`, version.AppName),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			maxChanges, _ := cmd.Flags().GetInt(flagMaxChanges)
+			stores, _ := cmd.Flags().GetStringSlice(flagStores)
+
+			var (
+				diffs []app.StateDiff
+				err   error
+			)
+			heightA, errA := strconv.ParseInt(args[0], 10, 64)
+			heightB, errB := strconv.ParseInt(args[1], 10, 64)
+			if errA == nil && errB == nil {
+				diffs, err = diffHeights(cmd, heightA, heightB, maxChanges, stores)
+			} else {
+				diffs, err = diffGenesisFiles(args[0], args[1], maxChanges, stores)
+			}
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString(cli.OutputFlag)
+			return printStateDiffs(cmd, diffs, output)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagHome, app.DefaultNodeHome, "The application home directory, whose heights are compared")
+	cmd.Flags().Int(flagMaxChanges, 100, "Max number of changes listed per module or store (0 for all)")
+	cmd.Flags().StringSlice(flagStores, []string{}, "Comma-separated list of the modules or stores to compare (default all)")
+	cmd.Flags().StringP(cli.OutputFlag, "o", "text", "Output format (text|json)")
+	return cmd
+}
+
+// diffHeights compares the application state under the home at the two heights
+func diffHeights(cmd *cobra.Command, heightA, heightB int64, maxChanges int, stores []string) ([]app.StateDiff, error) {
+	serverCtx := server.GetServerContextFromCmd(cmd)
+	home, _ := cmd.Flags().GetString(flags.FlagHome)
+
+	db, err := sdk.NewLevelDB("application", filepath.Join(home, "data"))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	appA, err := newExportApp(serverCtx.Logger, db, nil, heightA, serverCtx.Viper)
+	if err != nil {
+		return nil, err
+	}
+	appB, err := newExportApp(serverCtx.Logger, db, nil, heightB, serverCtx.Viper)
+	if err != nil {
+		return nil, err
+	}
+	return app.DiffStates(appA, appB, maxChanges, stores...)
+}
+
+// diffGenesisFiles compares the app states of the two genesis files module by module
+func diffGenesisFiles(fileA, fileB string, maxChanges int, modules []string) ([]app.StateDiff, error) {
+	appStateA, err := readAppState(fileA)
+	if err != nil {
+		return nil, err
+	}
+	appStateB, err := readAppState(fileB)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, appState := range []map[string]interface{}{appStateA, appStateB} {
+		for module := range appState {
+			if !seen[module] {
+				seen[module] = true
+				names = append(names, module)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	if len(modules) > 0 {
+		for _, module := range modules {
+			if !seen[module] {
+				return nil, fmt.Errorf("unknown module %s, expected one of %v", module, names)
+			}
+		}
+		names = modules
+	}
+
+	diffs := []app.StateDiff{}
+	for _, module := range names {
+		diff := app.StateDiff{Name: module, Changes: []app.StateChange{}}
+		diffJSON(module, appStateA[module], appStateB[module], func(change app.StateChange) {
+			diff.Add(change, maxChanges)
+		})
+		if diff.Added+diff.Updated+diff.Deleted > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+// readAppState reads the app state of the genesis file, keeping the numbers as they are
+func readAppState(file string) (map[string]interface{}, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var genesis struct {
+		AppState map[string]interface{} `json:"app_state"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	if err := dec.Decode(&genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file %s: %s", file, err)
+	}
+	return genesis.AppState, nil
+}
+
+// arrayIdentityKeys are the fields identifying the elements of the arrays, in the order they are tried
+var arrayIdentityKeys = []string{"address", "operator_address", "id", "denom"}
+
+// diffJSON calls the callback with the changes between the two decoded JSON values, walking the
+// objects by their keys and the arrays by the identity fields of their elements, or by their indexes
+// if the elements have no identity field
+func diffJSON(path string, a, b interface{}, cb func(app.StateChange)) {
+	switch {
+	case a == nil && b != nil:
+		cb(app.StateChange{Key: path, Change: app.ChangeAdded, B: compactJSON(b)})
+		return
+	case a != nil && b == nil:
+		cb(app.StateChange{Key: path, Change: app.ChangeDeleted, A: compactJSON(a)})
+		return
+	}
+
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(a)+len(b))
+			for key := range a {
+				keys = append(keys, key)
+			}
+			for key := range b {
+				if _, ok := a[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				diffJSON(path+"."+key, a[key], b[key], cb)
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			if key, ok := arrayIdentityKey(a, b); ok {
+				diffJSONArrayByKey(path, key, a, b, cb)
+				return
+			}
+			for i := 0; i < len(a) || i < len(b); i++ {
+				var elemA, elemB interface{}
+				if i < len(a) {
+					elemA = a[i]
+				}
+				if i < len(b) {
+					elemB = b[i]
+				}
+				diffJSON(fmt.Sprintf("%s[%d]", path, i), elemA, elemB, cb)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		cb(app.StateChange{Key: path, Change: app.ChangeUpdated, A: compactJSON(a), B: compactJSON(b)})
+	}
+}
+
+// diffJSONArrayByKey compares the elements of the arrays with the same identity, in the order of a,
+// followed by the elements only in b in their order
+func diffJSONArrayByKey(path, key string, a, b []interface{}, cb func(app.StateChange)) {
+	elemsB := make(map[string]interface{}, len(b))
+	for _, elem := range b {
+		elemsB[arrayIdentity(elem, key)] = elem
+	}
+
+	seen := make(map[string]bool, len(a))
+	for _, elem := range a {
+		id := arrayIdentity(elem, key)
+		seen[id] = true
+		diffJSON(fmt.Sprintf("%s[%s=%s]", path, key, id), elem, elemsB[id], cb)
+	}
+	for _, elem := range b {
+		if id := arrayIdentity(elem, key); !seen[id] {
+			diffJSON(fmt.Sprintf("%s[%s=%s]", path, key, id), nil, elem, cb)
+		}
+	}
+}
+
+// arrayIdentityKey returns the first identity field present in all the elements of the arrays and
+// unique within each of them
+func arrayIdentityKey(a, b []interface{}) (string, bool) {
+	if len(a) == 0 && len(b) == 0 {
+		return "", false
+	}
+
+	for _, key := range arrayIdentityKeys {
+		if isArrayIdentityKey(a, key) && isArrayIdentityKey(b, key) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func isArrayIdentityKey(elems []interface{}, key string) bool {
+	ids := make(map[string]bool, len(elems))
+	for _, elem := range elems {
+		obj, ok := elem.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		switch obj[key].(type) {
+		case string, json.Number:
+		default:
+			return false
+		}
+		id := arrayIdentity(obj, key)
+		if ids[id] {
+			return false
+		}
+		ids[id] = true
+	}
+	return true
+}
+
+func arrayIdentity(elem interface{}, key string) string {
+	return fmt.Sprintf("%v", elem.(map[string]interface{})[key])
+}
+
+func compactJSON(v interface{}) string {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(bz)
+}
+
+func printStateDiffs(cmd *cobra.Command, diffs []app.StateDiff, output string) error {
+	if output == formatJSON {
+		bz, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "No difference")
+		return w.Flush()
+	}
+
+	fmt.Fprintln(w, "NAME\tADDED\tUPDATED\tDELETED")
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", diff.Name, diff.Added, diff.Updated, diff.Deleted)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	for _, diff := range diffs {
+		fmt.Fprintf(out, "\n== %s\n", diff.Name)
+		for _, change := range diff.Changes {
+			fmt.Fprintf(out, "%s %s\n", change.Change, change.Key)
+			switch {
+			case change.Decoded != "":
+				fmt.Fprintf(out, "    %s\n", strings.ReplaceAll(strings.TrimSpace(change.Decoded), "\n", "\n    "))
+			default:
+				if change.A != "" {
+					fmt.Fprintf(out, "  - %s\n", change.A)
+				}
+				if change.B != "" {
+					fmt.Fprintf(out, "  + %s\n", change.B)
+				}
+			}
+		}
+		if diff.Truncated {
+			fmt.Fprintf(out, "... %d more changes\n", diff.Added+diff.Updated+diff.Deleted-len(diff.Changes))
+		}
+	}
+	return nil
+}
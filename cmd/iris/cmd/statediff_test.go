@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/irisnet/irishub/app"
+)
+
+func decodeTestJSON(t *testing.T, s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected []app.StateChange
+	}{
+		{"equal", `{"a":[1,2],"b":{"c":"d"}}`, `{"a":[1,2],"b":{"c":"d"}}`, nil},
+		{"added", "", `{"a":1}`, []app.StateChange{{Key: "m", Change: app.ChangeAdded, B: `{"a":1}`}}},
+		{"deleted", `{"a":1}`, "", []app.StateChange{{Key: "m", Change: app.ChangeDeleted, A: `{"a":1}`}}},
+		{
+			"nested object",
+			`{"a":{"b":1,"c":2},"d":3}`,
+			`{"a":{"b":1,"c":4,"e":5}}`,
+			[]app.StateChange{
+				{Key: "m.a.c", Change: app.ChangeUpdated, A: "2", B: "4"},
+				{Key: "m.a.e", Change: app.ChangeAdded, B: "5"},
+				{Key: "m.d", Change: app.ChangeDeleted, A: "3"},
+			},
+		},
+		{
+			"big numbers kept",
+			`{"a":"1","b":100000000000000000001}`,
+			`{"a":1,"b":100000000000000000002}`,
+			[]app.StateChange{
+				{Key: "m.a", Change: app.ChangeUpdated, A: `"1"`, B: "1"},
+				{Key: "m.b", Change: app.ChangeUpdated, A: "100000000000000000001", B: "100000000000000000002"},
+			},
+		},
+		{
+			"array by index",
+			`{"a":[1,2,3]}`,
+			`{"a":[1,4]}`,
+			[]app.StateChange{
+				{Key: "m.a[1]", Change: app.ChangeUpdated, A: "2", B: "4"},
+				{Key: "m.a[2]", Change: app.ChangeDeleted, A: "3"},
+			},
+		},
+		{
+			"array by address with an element inserted",
+			`{"balances":[{"address":"a","amount":1},{"address":"c","amount":3}]}`,
+			`{"balances":[{"address":"a","amount":1},{"address":"b","amount":2},{"address":"c","amount":4}]}`,
+			[]app.StateChange{
+				{Key: "m.balances[address=c].amount", Change: app.ChangeUpdated, A: "3", B: "4"},
+				{Key: "m.balances[address=b]", Change: app.ChangeAdded, B: `{"address":"b","amount":2}`},
+			},
+		},
+		{
+			"array by id with an element removed",
+			`[{"id":1,"v":"x"},{"id":2,"v":"y"},{"id":3,"v":"z"}]`,
+			`[{"id":1,"v":"x"},{"id":3,"v":"z"}]`,
+			[]app.StateChange{{Key: "m[id=2]", Change: app.ChangeDeleted, A: `{"id":2,"v":"y"}`}},
+		},
+		{
+			"array by denom reordered",
+			`[{"denom":"a","amount":"1"},{"denom":"b","amount":"2"}]`,
+			`[{"denom":"b","amount":"2"},{"denom":"a","amount":"1"}]`,
+			nil,
+		},
+		{
+			"address preferred to denom",
+			`[{"address":"x","denom":"a"}]`,
+			`[{"address":"x","denom":"b"}]`,
+			[]app.StateChange{{Key: "m[address=x].denom", Change: app.ChangeUpdated, A: `"a"`, B: `"b"`}},
+		},
+		{
+			"duplicate identities by index",
+			`[{"denom":"a","amount":"1"},{"denom":"a","amount":"2"}]`,
+			`[{"denom":"a","amount":"1"},{"denom":"a","amount":"3"}]`,
+			[]app.StateChange{{Key: "m[1].amount", Change: app.ChangeUpdated, A: `"2"`, B: `"3"`}},
+		},
+		{
+			"identity missing in an element by index",
+			`[{"id":"1"},{"v":"x"}]`,
+			`[{"id":"1"},{"v":"y"}]`,
+			[]app.StateChange{{Key: "m[1].v", Change: app.ChangeUpdated, A: `"x"`, B: `"y"`}},
+		},
+	}
+
+	for _, tc := range tests {
+		var changes []app.StateChange
+		diffJSON("m", decodeTestJSON(t, tc.a), decodeTestJSON(t, tc.b), func(change app.StateChange) {
+			changes = append(changes, change)
+		})
+		require.Equal(t, tc.expected, changes, tc.name)
+	}
+}
+
+func TestDiffGenesisFiles(t *testing.T) {
+	dir := t.TempDir()
+	fileA := writeTestFile(t, dir, "a.json", `{"chain_id":"a","app_state":{
+		"bank":{"balances":[{"address":"x","coins":[{"denom":"uiris","amount":"1"}]}]},
+		"htlc":{"htlcs":[]},
+		"token":{"tokens":[{"symbol":"iris"}]}
+	}}`)
+	fileB := writeTestFile(t, dir, "b.json", `{"chain_id":"b","app_state":{
+		"bank":{"balances":[{"address":"y","coins":[]},{"address":"x","coins":[{"denom":"uiris","amount":"2"}]}]},
+		"htlc":{"htlcs":[]},
+		"memo":{}
+	}}`)
+
+	diffs, err := diffGenesisFiles(fileA, fileB, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, []app.StateDiff{
+		{
+			Name:    "bank",
+			Added:   1,
+			Updated: 1,
+			Changes: []app.StateChange{
+				{Key: "bank.balances[address=x].coins[denom=uiris].amount", Change: app.ChangeUpdated, A: `"1"`, B: `"2"`},
+				{Key: "bank.balances[address=y]", Change: app.ChangeAdded, B: `{"address":"y","coins":[]}`},
+			},
+		},
+		{Name: "memo", Added: 1, Changes: []app.StateChange{{Key: "memo", Change: app.ChangeAdded, B: "{}"}}},
+		{Name: "token", Deleted: 1, Changes: []app.StateChange{{Key: "token", Change: app.ChangeDeleted, A: `{"tokens":[{"symbol":"iris"}]}`}}},
+	}, diffs)
+
+	// the modules are filtered, and the changes truncated
+	diffs, err = diffGenesisFiles(fileA, fileB, 1, []string{"bank", "htlc"})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, "bank", diffs[0].Name)
+	require.Len(t, diffs[0].Changes, 1)
+	require.True(t, diffs[0].Truncated)
+
+	// the modules in neither file are rejected
+	_, err = diffGenesisFiles(fileA, fileB, 0, []string{"bank", "unknown"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown module unknown")
+
+	_, err = diffGenesisFiles(fileA, writeTestFile(t, dir, "invalid.json", "{"), 0, nil)
+	require.Error(t, err)
+}
@@ -34,7 +34,7 @@ import (
 	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
-	guardiantypes "github.com/irisnet/irishub/modules/guardian/types"
+	"github.com/irisnet/irishub/address"
 )
 
 var (
@@ -56,6 +56,8 @@ necessary files (private validator, genesis, config, etc.).
 Note, strict routability for addresses is turned off in the config file.
 Example:
 	iris testnet --v 4 --output-dir ./output --starting-ip-address 192.168.10.2
+	iris testnet --v 4 --bond-denom uiris --accounts-file accounts.json --genesis-overrides overrides.json
+	iris testnet --v 4 --guardian-supers node0 --tokens-file tokens.json
 	`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			clientCtx := client.GetClientContextFromCmd(cmd)
@@ -74,9 +76,18 @@ Example:
 			numValidators, _ := cmd.Flags().GetInt(flagNumValidators)
 			algo, _ := cmd.Flags().GetString(flags.FlagKeyAlgorithm)
 
+			genesisOpts, err := parseTestnetGenesisOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed(server.FlagMinGasPrices) {
+				minGasPrices = fmt.Sprintf("0.000006%s", genesisOpts.bondDenom)
+			}
+
 			return InitTestnet(
 				clientCtx, cmd, config, mbm, genBalIterator, outputDir, chainID, minGasPrices,
 				nodeDirPrefix, nodeDaemonHome, nodeCLIHome, startingIPAddress, keyringBackend, algo, numValidators,
+				genesisOpts,
 			)
 		},
 	}
@@ -91,6 +102,7 @@ Example:
 	cmd.Flags().String(server.FlagMinGasPrices, fmt.Sprintf("0.000006%s", sdk.DefaultBondDenom), "Minimum gas prices to accept for transactions; All fees in a tx must meet this minimum (e.g. 0.01photino,0.001stake)")
 	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|test)")
 	cmd.Flags().String(flags.FlagKeyAlgorithm, string(hd.Secp256k1Type), "Key signing algorithm to generate keys for")
+	addTestnetGenesisFlags(cmd)
 
 	return cmd
 }
@@ -114,6 +126,7 @@ func InitTestnet(
 	keyringBackend,
 	algoStr string,
 	numValidators int,
+	genesisOpts testnetGenesisOptions,
 ) error {
 	if chainID == "" {
 		chainID = "chain-" + tmrand.NewRand().Str(6)
@@ -134,6 +147,7 @@ func InitTestnet(
 		genAccounts []authtypes.GenesisAccount
 		genBalances []banktypes.Balance
 		genFiles    []string
+		nodeAddrs   = make(map[string]sdk.AccAddress)
 	)
 
 	inBuf := bufio.NewReader(cmd.InOrStdin())
@@ -191,6 +205,8 @@ func InitTestnet(
 			return err
 		}
 
+		nodeAddrs[nodeDirName] = addr
+
 		info := map[string]string{"secret": secret}
 
 		cliPrint, err := json.Marshal(info)
@@ -203,21 +219,18 @@ func InitTestnet(
 			return err
 		}
 
-		accTokens := sdk.TokensFromConsensusPower(1000)
-		accStakingTokens := sdk.TokensFromConsensusPower(500)
-		coins := sdk.Coins{
-			sdk.NewCoin(fmt.Sprintf("%stoken", nodeDirName), accTokens),
-			sdk.NewCoin(sdk.DefaultBondDenom, accStakingTokens),
-		}
+		coins := sdk.NewCoins(
+			sdk.NewCoin(fmt.Sprintf("%stoken", nodeDirName), genesisOpts.nodeTokens),
+			sdk.NewCoin(genesisOpts.bondDenom, genesisOpts.stakingTokens),
+		)
 
 		genBalances = append(genBalances, banktypes.Balance{Address: addr.String(), Coins: coins.Sort()})
 		genAccounts = append(genAccounts, authtypes.NewBaseAccount(addr, nil, 0, 0))
 
-		valTokens := sdk.TokensFromConsensusPower(100)
 		createValMsg, err := stakingtypes.NewMsgCreateValidator(
 			sdk.ValAddress(addr),
 			valPubKeys[i],
-			sdk.NewCoin(sdk.DefaultBondDenom, valTokens),
+			sdk.NewCoin(genesisOpts.bondDenom, genesisOpts.selfDelegation),
 			stakingtypes.NewDescription(nodeDirName, "", "", "", ""),
 			stakingtypes.NewCommissionRates(sdk.OneDec(), sdk.OneDec(), sdk.OneDec()),
 			sdk.OneInt(),
@@ -254,13 +267,20 @@ func InitTestnet(
 		}
 
 		srvconfig.WriteConfigFile(filepath.Join(nodeDir, "config/app.toml"), simappConfig)
+		if err := writeBech32PrefixConfig(nodeDir, address.GetBech32PrefixRoot()); err != nil {
+			return err
+		}
 	}
 
-	if err := initGenFiles(clientCtx, mbm, chainID, genAccounts, genBalances, genFiles, numValidators); err != nil {
+	err := initGenFiles(
+		clientCtx, mbm, chainID, genAccounts, genBalances, genFiles, numValidators,
+		genesisOpts, nodeAddrs, fmt.Sprintf("%s%d", nodeDirPrefix, 0),
+	)
+	if err != nil {
 		return err
 	}
 
-	err := collectGenFiles(
+	err = collectGenFiles(
 		clientCtx, nodeConfig, chainID, nodeIDs, valPubKeys, numValidators,
 		outputDir, nodeDirPrefix, nodeDaemonHome, genBalIterator,
 	)
@@ -275,22 +295,30 @@ func InitTestnet(
 func initGenFiles(
 	clientCtx client.Context, mbm module.BasicManager, chainID string,
 	genAccounts []authtypes.GenesisAccount, genBalances []banktypes.Balance,
-	genFiles []string, numValidators int,
+	genFiles []string, numValidators int, genesisOpts testnetGenesisOptions,
+	nodeAddrs map[string]sdk.AccAddress, firstNode string,
 ) error {
 	appGenState := mbm.DefaultGenesis(clientCtx.JSONMarshaler)
+	if err := setBondDenom(appGenState, genesisOpts.bondDenom); err != nil {
+		return err
+	}
 
 	// add the profiler and trustees in the genesis state
-	var guardianGenState guardiantypes.GenesisState
-	clientCtx.JSONMarshaler.MustUnmarshalJSON(appGenState[guardiantypes.ModuleName], &guardianGenState)
+	if err := addGuardianSupers(clientCtx.JSONMarshaler, appGenState, genesisOpts, nodeAddrs, genAccounts); err != nil {
+		return err
+	}
 
-	for _, account := range genAccounts {
-		guardian := guardiantypes.NewSuper(
-			"genesis", guardiantypes.Genesis,
-			account.GetAddress(), account.GetAddress(),
-		)
-		guardianGenState.Supers = append(guardianGenState.Supers, guardian)
+	// add the extra accounts after the validator accounts, and the tokens issued to their owners
+	genAccounts, genBalances, err := addTestnetAccounts(genesisOpts, genAccounts, genBalances)
+	if err != nil {
+		return err
+	}
+	genAccounts, genBalances, err = issueTestnetTokens(
+		clientCtx.JSONMarshaler, appGenState, genesisOpts, nodeAddrs, firstNode, genAccounts, genBalances,
+	)
+	if err != nil {
+		return err
 	}
-	appGenState[guardiantypes.ModuleName] = clientCtx.JSONMarshaler.MustMarshalJSON(&guardianGenState)
 
 	// set the accounts in the genesis state
 	var authGenState authtypes.GenesisState
@@ -311,6 +339,13 @@ func initGenFiles(
 	bankGenState.Balances = genBalances
 	appGenState[banktypes.ModuleName] = clientCtx.JSONMarshaler.MustMarshalJSON(&bankGenState)
 
+	if err := applyGenesisOverrides(appGenState, genesisOpts.overrides); err != nil {
+		return err
+	}
+	if err := mbm.ValidateGenesis(clientCtx.JSONMarshaler, clientCtx.TxConfig, appGenState); err != nil {
+		return fmt.Errorf("invalid genesis state of the testnet: %w", err)
+	}
+
 	appGenStateJSON, err := json.MarshalIndent(appGenState, "", "  ")
 	if err != nil {
 		return err
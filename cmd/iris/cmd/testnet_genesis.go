@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	tokentypes "github.com/irisnet/irismod/modules/token/types"
+
+	guardiantypes "github.com/irisnet/irishub/modules/guardian/types"
+)
+
+const (
+	flagGenesisOverrides = "genesis-overrides"
+	flagAccountsFile     = "accounts-file"
+	flagNodeTokens       = "node-tokens"
+	flagStakingTokens    = "staking-tokens"
+	flagSelfDelegation   = "self-delegation"
+	flagBondDenom        = "bond-denom"
+	flagGuardianSupers   = "guardian-supers"
+	flagTokensFile       = "tokens-file"
+)
+
+// testnetGenesisOptions defines the genesis of the testnet beyond the default genesis of the modules
+type testnetGenesisOptions struct {
+	bondDenom      string
+	nodeTokens     sdk.Int // tokens of <node>token given to each validator account
+	stakingTokens  sdk.Int // tokens of the bond denom given to each validator account
+	selfDelegation sdk.Int // tokens of the bond denom self-delegated by each validator
+	accounts       []banktypes.Balance
+	overrides      map[string]json.RawMessage
+	guardianSupers []string // node names or addresses of the genesis supers, all the validators if empty
+	tokens         []testnetToken
+}
+
+// testnetToken is a token issued in the genesis, whose initial supply is given to its owner
+type testnetToken struct {
+	Symbol        string `json:"symbol"`
+	Name          string `json:"name"`
+	MinUnit       string `json:"min_unit"`
+	Scale         uint32 `json:"scale"`
+	InitialSupply uint64 `json:"initial_supply"`
+	MaxSupply     uint64 `json:"max_supply"`
+	Mintable      bool   `json:"mintable"`
+	Owner         string `json:"owner"` // node name or address, the first node if empty
+}
+
+func addTestnetGenesisFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagGenesisOverrides, "", "JSON object, or the path of a JSON file, deep-merged into the app state of the genesis (e.g. '{\"staking\":{\"params\":{\"unbonding_time\":\"60s\"}}}')")
+	cmd.Flags().String(flagAccountsFile, "", "Path of a JSON file of extra funded genesis accounts (e.g. '[{\"address\":\"iaa1...\",\"coins\":\"1000000uiris\"}]')")
+	cmd.Flags().String(flagNodeTokens, sdk.TokensFromConsensusPower(1000).String(), "Amount of <node>token given to each validator account")
+	cmd.Flags().String(flagStakingTokens, sdk.TokensFromConsensusPower(500).String(), "Amount of the bond denom given to each validator account")
+	cmd.Flags().String(flagSelfDelegation, sdk.TokensFromConsensusPower(100).String(), "Amount of the bond denom self-delegated by each validator")
+	cmd.Flags().String(flagBondDenom, sdk.DefaultBondDenom, "Bond denom of the testnet, replacing the default one in the genesis of all the modules (e.g. uiris as on the mainnet)")
+	cmd.Flags().StringSlice(flagGuardianSupers, []string{}, "Comma-separated list of the node names or addresses made genesis supers of the guardian module (default all the validators)")
+	cmd.Flags().String(flagTokensFile, "", "Path of a JSON file of the tokens issued in the genesis, given to their owners (e.g. '[{\"symbol\":\"btc\",\"name\":\"Bitcoin\",\"min_unit\":\"satoshi\",\"scale\":8,\"initial_supply\":21000000,\"mintable\":true,\"owner\":\"node0\"}]')")
+}
+
+func parseTestnetGenesisOptions(cmd *cobra.Command) (opts testnetGenesisOptions, err error) {
+	opts.bondDenom, _ = cmd.Flags().GetString(flagBondDenom)
+	if err := sdk.ValidateDenom(opts.bondDenom); err != nil {
+		return opts, fmt.Errorf("invalid bond denom: %w", err)
+	}
+
+	for flag, amount := range map[string]*sdk.Int{
+		flagNodeTokens:     &opts.nodeTokens,
+		flagStakingTokens:  &opts.stakingTokens,
+		flagSelfDelegation: &opts.selfDelegation,
+	} {
+		str, _ := cmd.Flags().GetString(flag)
+		value, ok := sdk.NewIntFromString(str)
+		if !ok || value.IsNegative() {
+			return opts, fmt.Errorf("invalid --%s %s, which must be a non-negative integer", flag, str)
+		}
+		*amount = value
+	}
+	if !opts.selfDelegation.IsPositive() || opts.selfDelegation.GT(opts.stakingTokens) {
+		return opts, fmt.Errorf(
+			"invalid --%s %s, which must be positive and not exceed --%s %s",
+			flagSelfDelegation, opts.selfDelegation, flagStakingTokens, opts.stakingTokens,
+		)
+	}
+
+	if accountsFile, _ := cmd.Flags().GetString(flagAccountsFile); accountsFile != "" {
+		if opts.accounts, err = readTestnetAccounts(accountsFile); err != nil {
+			return opts, err
+		}
+	}
+
+	opts.guardianSupers, _ = cmd.Flags().GetStringSlice(flagGuardianSupers)
+
+	if tokensFile, _ := cmd.Flags().GetString(flagTokensFile); tokensFile != "" {
+		bz, err := ioutil.ReadFile(tokensFile)
+		if err != nil {
+			return opts, err
+		}
+		if err := json.Unmarshal(bz, &opts.tokens); err != nil {
+			return opts, fmt.Errorf("invalid tokens file %s: %w", tokensFile, err)
+		}
+	}
+
+	if overrides, _ := cmd.Flags().GetString(flagGenesisOverrides); overrides != "" {
+		bz := []byte(overrides)
+		if !strings.HasPrefix(strings.TrimSpace(overrides), "{") {
+			if bz, err = ioutil.ReadFile(overrides); err != nil {
+				return opts, err
+			}
+		}
+		if err := json.Unmarshal(bz, &opts.overrides); err != nil {
+			return opts, fmt.Errorf("invalid genesis overrides: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+// readTestnetAccounts reads the accounts file, a JSON array of addresses with their coins
+func readTestnetAccounts(file string) ([]banktypes.Balance, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []struct {
+		Address string `json:"address"`
+		Coins   string `json:"coins"`
+	}
+	if err := json.Unmarshal(bz, &accounts); err != nil {
+		return nil, fmt.Errorf("invalid accounts file %s: %w", file, err)
+	}
+
+	balances := make([]banktypes.Balance, len(accounts))
+	for i, account := range accounts {
+		addr, err := sdk.AccAddressFromBech32(account.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address of account %d in %s: %w", i, file, err)
+		}
+		coins, err := sdk.ParseCoinsNormalized(account.Coins)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coins of account %s in %s: %w", addr, file, err)
+		}
+		balances[i] = banktypes.Balance{Address: addr.String(), Coins: coins}
+	}
+	return balances, nil
+}
+
+// addTestnetAccounts adds the extra accounts of the options to the validator accounts
+func addTestnetAccounts(
+	opts testnetGenesisOptions, genAccounts []authtypes.GenesisAccount, genBalances []banktypes.Balance,
+) ([]authtypes.GenesisAccount, []banktypes.Balance, error) {
+	for _, balance := range opts.accounts {
+		addr := balance.GetAddress()
+		if authtypes.GenesisAccounts(genAccounts).Contains(addr) {
+			return nil, nil, fmt.Errorf("duplicate genesis account %s", addr)
+		}
+		genAccounts = append(genAccounts, authtypes.NewBaseAccount(addr, nil, 0, 0))
+		genBalances = append(genBalances, balance)
+	}
+	return genAccounts, banktypes.SanitizeGenesisBalances(genBalances), nil
+}
+
+// resolveTestnetAddress returns the account address of the node with the given name, or the given address
+func resolveTestnetAddress(nameOrAddress string, nodeAddrs map[string]sdk.AccAddress) (sdk.AccAddress, error) {
+	if addr, ok := nodeAddrs[nameOrAddress]; ok {
+		return addr, nil
+	}
+	addr, err := sdk.AccAddressFromBech32(nameOrAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a node name nor a valid address: %w", nameOrAddress, err)
+	}
+	return addr, nil
+}
+
+// addGuardianSupers adds the genesis supers of the options, or all the validators if none is given,
+// to the genesis state of the guardian module. A super given more than once, by its node name or its
+// address, is added once.
+func addGuardianSupers(
+	cdc codec.JSONMarshaler, appState map[string]json.RawMessage, opts testnetGenesisOptions,
+	nodeAddrs map[string]sdk.AccAddress, validators []authtypes.GenesisAccount,
+) error {
+	var supers []sdk.AccAddress
+	seen := make(map[string]bool)
+	for _, super := range opts.guardianSupers {
+		addr, err := resolveTestnetAddress(super, nodeAddrs)
+		if err != nil {
+			return fmt.Errorf("invalid guardian super: %w", err)
+		}
+		if seen[addr.String()] {
+			continue
+		}
+		seen[addr.String()] = true
+		supers = append(supers, addr)
+	}
+	if len(opts.guardianSupers) == 0 {
+		for _, account := range validators {
+			supers = append(supers, account.GetAddress())
+		}
+	}
+
+	var guardianGenState guardiantypes.GenesisState
+	cdc.MustUnmarshalJSON(appState[guardiantypes.ModuleName], &guardianGenState)
+
+	for _, addr := range supers {
+		guardian := guardiantypes.NewSuper("genesis", guardiantypes.Genesis, addr, addr)
+		guardianGenState.Supers = append(guardianGenState.Supers, guardian)
+	}
+	appState[guardiantypes.ModuleName] = cdc.MustMarshalJSON(&guardianGenState)
+	return nil
+}
+
+// issueTestnetTokens adds the tokens of the options to the genesis state of the token module and
+// gives their initial supply to their owners, adding the accounts of the owners if needed
+func issueTestnetTokens(
+	cdc codec.JSONMarshaler, appState map[string]json.RawMessage, opts testnetGenesisOptions,
+	nodeAddrs map[string]sdk.AccAddress, firstNode string,
+	genAccounts []authtypes.GenesisAccount, genBalances []banktypes.Balance,
+) ([]authtypes.GenesisAccount, []banktypes.Balance, error) {
+	if len(opts.tokens) == 0 {
+		return genAccounts, genBalances, nil
+	}
+
+	var tokenGenState tokentypes.GenesisState
+	cdc.MustUnmarshalJSON(appState[tokentypes.ModuleName], &tokenGenState)
+
+	for _, t := range opts.tokens {
+		if t.Owner == "" {
+			t.Owner = firstNode
+		}
+		owner, err := resolveTestnetAddress(t.Owner, nodeAddrs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid owner of token %s: %w", t.Symbol, err)
+		}
+
+		token := tokentypes.NewToken(t.Symbol, t.Name, t.MinUnit, t.Scale, t.InitialSupply, t.MaxSupply, t.Mintable, owner)
+		if err := tokentypes.ValidateToken(token); err != nil {
+			return nil, nil, fmt.Errorf("invalid token %s: %w", t.Symbol, err)
+		}
+		for _, issued := range tokenGenState.Tokens {
+			if issued.Symbol == token.Symbol || issued.MinUnit == token.MinUnit {
+				return nil, nil, fmt.Errorf("token %s (%s) is already issued", token.Symbol, token.MinUnit)
+			}
+		}
+		tokenGenState.Tokens = append(tokenGenState.Tokens, token)
+
+		supply := sdk.NewCoin(token.MinUnit, sdk.NewIntFromUint64(token.InitialSupply).Mul(sdk.NewIntWithDecimal(1, int(token.Scale))))
+		if !authtypes.GenesisAccounts(genAccounts).Contains(owner) {
+			genAccounts = append(genAccounts, authtypes.NewBaseAccount(owner, nil, 0, 0))
+		}
+		genBalances = addGenesisBalance(genBalances, owner, supply)
+	}
+	appState[tokentypes.ModuleName] = cdc.MustMarshalJSON(&tokenGenState)
+	return genAccounts, banktypes.SanitizeGenesisBalances(genBalances), nil
+}
+
+func addGenesisBalance(balances []banktypes.Balance, addr sdk.AccAddress, coin sdk.Coin) []banktypes.Balance {
+	for i, balance := range balances {
+		if balance.Address == addr.String() {
+			balances[i].Coins = balance.Coins.Add(coin)
+			return balances
+		}
+	}
+	return append(balances, banktypes.Balance{Address: addr.String(), Coins: sdk.NewCoins(coin)})
+}
+
+// setBondDenom replaces the default bond denom by the given one in the denom fields of the app state
+func setBondDenom(appState map[string]json.RawMessage, bondDenom string) error {
+	if bondDenom == sdk.DefaultBondDenom {
+		return nil
+	}
+
+	var replace func(key string, value interface{}) interface{}
+	replace = func(key string, value interface{}) interface{} {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for k, v := range value {
+				value[k] = replace(k, v)
+			}
+		case []interface{}:
+			for i, v := range value {
+				value[i] = replace(key, v)
+			}
+		case string:
+			if value == sdk.DefaultBondDenom && (key == "denom" || strings.HasSuffix(key, "_denom")) {
+				return bondDenom
+			}
+		}
+		return value
+	}
+
+	for module, state := range appState {
+		value, err := decodeJSON(state)
+		if err != nil {
+			return err
+		}
+		if appState[module], err = json.Marshal(replace("", value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyGenesisOverrides deep-merges the overrides into the genesis states of the modules: the objects
+// are merged field by field, while any other value replaces the existing one
+func applyGenesisOverrides(appState, overrides map[string]json.RawMessage) error {
+	for module, override := range overrides {
+		state, ok := appState[module]
+		if !ok {
+			return fmt.Errorf("invalid genesis overrides: unknown module %s", module)
+		}
+
+		dst, err := decodeJSON(state)
+		if err != nil {
+			return err
+		}
+		src, err := decodeJSON(override)
+		if err != nil {
+			return fmt.Errorf("invalid genesis overrides of module %s: %w", module, err)
+		}
+		if appState[module], err = json.Marshal(deepMerge(dst, src)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deepMerge(dst, src interface{}) interface{} {
+	dstMap, ok := dst.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	srcMap, ok := src.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	for key, value := range srcMap {
+		dstMap[key] = deepMerge(dstMap[key], value)
+	}
+	return dstMap
+}
+
+// decodeJSON decodes the JSON value, keeping the numbers as they are
+func decodeJSON(bz json.RawMessage) (value interface{}, err error) {
+	if len(bz) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	err = dec.Decode(&value)
+	return value, err
+}
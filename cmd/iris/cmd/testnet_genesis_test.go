@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	tokentypes "github.com/irisnet/irismod/modules/token/types"
+
+	"github.com/irisnet/irishub/app"
+	"github.com/irisnet/irishub/modules/guardian"
+	guardiantypes "github.com/irisnet/irishub/modules/guardian/types"
+)
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		dst, src string
+		expected string
+	}{
+		{"scalar replaced", `1`, `2`, `2`},
+		{"object replacing a scalar", `1`, `{"a":1}`, `{"a":1}`},
+		{"scalar replacing an object", `{"a":1}`, `1`, `1`},
+		{"fields merged", `{"a":1,"b":2}`, `{"b":3,"c":4}`, `{"a":1,"b":3,"c":4}`},
+		{"nested objects merged", `{"a":{"b":1,"c":{"d":2,"e":3}}}`, `{"a":{"c":{"d":4}}}`, `{"a":{"b":1,"c":{"d":4,"e":3}}}`},
+		{"array replaced", `{"a":[1,2,3]}`, `{"a":[4]}`, `{"a":[4]}`},
+		{"array of objects replaced", `{"a":[{"b":1,"c":2}]}`, `{"a":[{"b":3}]}`, `{"a":[{"b":3}]}`},
+		{"null replacing", `{"a":{"b":1}}`, `{"a":null}`, `{"a":null}`},
+		{"big numbers kept", `{"a":1}`, `{"a":100000000000000000001}`, `{"a":100000000000000000001}`},
+	}
+
+	for _, tc := range tests {
+		dst, err := decodeJSON(json.RawMessage(tc.dst))
+		require.NoError(t, err, tc.name)
+		src, err := decodeJSON(json.RawMessage(tc.src))
+		require.NoError(t, err, tc.name)
+
+		merged, err := json.Marshal(deepMerge(dst, src))
+		require.NoError(t, err, tc.name)
+		require.JSONEq(t, tc.expected, string(merged), tc.name)
+	}
+}
+
+func TestApplyGenesisOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides string
+		expected  map[string]string
+		errMsg    string
+	}{
+		{
+			"nested object",
+			`{"staking":{"params":{"unbonding_time":"60s"}}}`,
+			map[string]string{
+				"staking": `{"params":{"unbonding_time":"60s","max_validators":100},"validators":[{"a":1}]}`,
+				"gov":     `{"deposit_params":{"min_deposit":[{"denom":"stake","amount":"10"}]}}`,
+			},
+			"",
+		},
+		{
+			"array",
+			`{"gov":{"deposit_params":{"min_deposit":[{"denom":"uiris","amount":"1"}]}},"staking":{"validators":[]}}`,
+			map[string]string{
+				"staking": `{"params":{"unbonding_time":"3s","max_validators":100},"validators":[]}`,
+				"gov":     `{"deposit_params":{"min_deposit":[{"denom":"uiris","amount":"1"}]}}`,
+			},
+			"",
+		},
+		{"unknown module", `{"unknown":{}}`, nil, "unknown module unknown"},
+	}
+
+	for _, tc := range tests {
+		appState := map[string]json.RawMessage{
+			"staking": json.RawMessage(`{"params":{"unbonding_time":"3s","max_validators":100},"validators":[{"a":1}]}`),
+			"gov":     json.RawMessage(`{"deposit_params":{"min_deposit":[{"denom":"stake","amount":"10"}]}}`),
+		}
+		var overrides map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(tc.overrides), &overrides), tc.name)
+
+		err := applyGenesisOverrides(appState, overrides)
+		if tc.expected == nil {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		for module, expected := range tc.expected {
+			require.JSONEq(t, expected, string(appState[module]), tc.name)
+		}
+	}
+}
+
+func TestSetBondDenom(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+
+	tests := []struct {
+		name      string
+		bondDenom string
+	}{
+		{"default", sdk.DefaultBondDenom},
+		{"mainnet", "uiris"},
+	}
+
+	for _, tc := range tests {
+		appState := app.ModuleBasics.DefaultGenesis(encodingConfig.Marshaler)
+		require.NoError(t, setBondDenom(appState, tc.bondDenom), tc.name)
+		require.NoError(t, app.ModuleBasics.ValidateGenesis(encodingConfig.Marshaler, encodingConfig.TxConfig, appState), tc.name)
+
+		var stakingGenState stakingtypes.GenesisState
+		encodingConfig.Marshaler.MustUnmarshalJSON(appState[stakingtypes.ModuleName], &stakingGenState)
+		require.Equal(t, tc.bondDenom, stakingGenState.Params.BondDenom, tc.name)
+
+		if tc.bondDenom != sdk.DefaultBondDenom {
+			for module, state := range appState {
+				require.NotContains(t, string(state), `"`+sdk.DefaultBondDenom+`"`, module)
+			}
+		}
+	}
+}
+
+func TestReadTestnetAccounts(t *testing.T) {
+	dir := t.TempDir()
+	addr1, addr2 := sdk.AccAddress("account1____________"), sdk.AccAddress("account2____________")
+
+	tests := []struct {
+		name     string
+		accounts string
+		expected []banktypes.Balance
+		errMsg   string
+	}{
+		{
+			"accounts",
+			`[{"address":"` + addr1.String() + `","coins":"10uiris,5btc"},{"address":"` + addr2.String() + `","coins":""}]`,
+			[]banktypes.Balance{
+				{Address: addr1.String(), Coins: sdk.NewCoins(sdk.NewInt64Coin("btc", 5), sdk.NewInt64Coin("uiris", 10))},
+				{Address: addr2.String(), Coins: nil},
+			},
+			"",
+		},
+		{"empty", `[]`, []banktypes.Balance{}, ""},
+		{"invalid address", `[{"address":"iaa1invalid","coins":"10uiris"}]`, nil, "invalid address of account 0"},
+		{"invalid coins", `[{"address":"` + addr1.String() + `","coins":"ten"}]`, nil, "invalid coins of account"},
+		{"not a list", `{"address":"` + addr1.String() + `"}`, nil, "invalid accounts file"},
+	}
+
+	for _, tc := range tests {
+		balances, err := readTestnetAccounts(writeTestFile(t, dir, "accounts.json", tc.accounts))
+		if tc.errMsg != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, balances, tc.name)
+	}
+
+	_, err := readTestnetAccounts(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
+
+func TestAddTestnetAccounts(t *testing.T) {
+	validator, extra := sdk.AccAddress("validator___________"), sdk.AccAddress("extra_______________")
+	validatorBalance := banktypes.Balance{Address: validator.String(), Coins: sdk.NewCoins(sdk.NewInt64Coin("stake", 10))}
+	extraBalance := banktypes.Balance{Address: extra.String(), Coins: sdk.NewCoins(sdk.NewInt64Coin("uiris", 5))}
+
+	tests := []struct {
+		name             string
+		accounts         []banktypes.Balance
+		expectedAccounts []sdk.AccAddress
+		errMsg           string
+	}{
+		{"no extra accounts", nil, []sdk.AccAddress{validator}, ""},
+		{"extra account", []banktypes.Balance{extraBalance}, []sdk.AccAddress{validator, extra}, ""},
+		{"validator account", []banktypes.Balance{validatorBalance}, nil, "duplicate genesis account"},
+		{"duplicate extra accounts", []banktypes.Balance{extraBalance, extraBalance}, nil, "duplicate genesis account"},
+	}
+
+	for _, tc := range tests {
+		genAccounts := []authtypes.GenesisAccount{authtypes.NewBaseAccount(validator, nil, 0, 0)}
+		genBalances := []banktypes.Balance{validatorBalance}
+
+		genAccounts, genBalances, err := addTestnetAccounts(testnetGenesisOptions{accounts: tc.accounts}, genAccounts, genBalances)
+		if tc.errMsg != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+
+		addrs := make([]sdk.AccAddress, len(genAccounts))
+		for i, account := range genAccounts {
+			addrs[i] = account.GetAddress()
+		}
+		require.Equal(t, tc.expectedAccounts, addrs, tc.name)
+		require.Equal(t, banktypes.SanitizeGenesisBalances(append([]banktypes.Balance{validatorBalance}, tc.accounts...)), genBalances, tc.name)
+	}
+}
+
+func TestAddGuardianSupers(t *testing.T) {
+	cdc := app.MakeEncodingConfig().Marshaler
+	node0, node1, other := sdk.AccAddress("node0_______________"), sdk.AccAddress("node1_______________"), sdk.AccAddress("other_______________")
+	nodeAddrs := map[string]sdk.AccAddress{"node0": node0, "node1": node1}
+	validators := []authtypes.GenesisAccount{authtypes.NewBaseAccount(node0, nil, 0, 0), authtypes.NewBaseAccount(node1, nil, 0, 0)}
+
+	tests := []struct {
+		name     string
+		supers   []string
+		expected []sdk.AccAddress
+		errMsg   string
+	}{
+		{"all the validators by default", nil, []sdk.AccAddress{node0, node1}, ""},
+		{"node name", []string{"node1"}, []sdk.AccAddress{node1}, ""},
+		{"address", []string{other.String()}, []sdk.AccAddress{other}, ""},
+		{"node name and address", []string{"node0", other.String()}, []sdk.AccAddress{node0, other}, ""},
+		{"duplicate node names", []string{"node0", "node0"}, []sdk.AccAddress{node0}, ""},
+		{"node name and its address", []string{"node0", node0.String(), "node1"}, []sdk.AccAddress{node0, node1}, ""},
+		{"unknown node name", []string{"node2"}, nil, "node2 is neither a node name nor a valid address"},
+	}
+
+	for _, tc := range tests {
+		appState := app.ModuleBasics.DefaultGenesis(cdc)
+		err := addGuardianSupers(cdc, appState, testnetGenesisOptions{guardianSupers: tc.supers}, nodeAddrs, validators)
+		if tc.errMsg != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+
+		var guardianGenState guardiantypes.GenesisState
+		cdc.MustUnmarshalJSON(appState[guardiantypes.ModuleName], &guardianGenState)
+		require.NoError(t, guardian.ValidateGenesis(guardianGenState), tc.name)
+
+		supers := make([]sdk.AccAddress, len(guardianGenState.Supers))
+		for i, super := range guardianGenState.Supers {
+			require.Equal(t, guardiantypes.Genesis, super.AccountType, tc.name)
+			supers[i] = mustAccAddress(t, super.Address)
+		}
+		require.Equal(t, tc.expected, supers, tc.name)
+	}
+}
+
+func TestIssueTestnetTokens(t *testing.T) {
+	cdc := app.MakeEncodingConfig().Marshaler
+	node0, node1, other := sdk.AccAddress("node0_______________"), sdk.AccAddress("node1_______________"), sdk.AccAddress("other_______________")
+	nodeAddrs := map[string]sdk.AccAddress{"node0": node0, "node1": node1}
+
+	appState := app.ModuleBasics.DefaultGenesis(cdc)
+	genAccounts := []authtypes.GenesisAccount{authtypes.NewBaseAccount(node0, nil, 0, 0), authtypes.NewBaseAccount(node1, nil, 0, 0)}
+	genBalances := []banktypes.Balance{
+		{Address: node0.String(), Coins: sdk.NewCoins(sdk.NewInt64Coin("stake", 10))},
+		{Address: node1.String(), Coins: sdk.NewCoins(sdk.NewInt64Coin("stake", 10))},
+	}
+
+	opts := testnetGenesisOptions{tokens: []testnetToken{
+		{Symbol: "btc", Name: "Bitcoin", MinUnit: "satoshi", Scale: 8, InitialSupply: 21000000, MaxSupply: 21000000, Mintable: true},
+		{Symbol: "eth", Name: "Ethereum", MinUnit: "gwei", Scale: 9, InitialSupply: 1000, MaxSupply: 1000000, Owner: "node1"},
+		{Symbol: "usdt", Name: "Tether", MinUnit: "uusdt", Scale: 6, InitialSupply: 5, MaxSupply: 100, Owner: other.String()},
+	}}
+	genAccounts, genBalances, err := issueTestnetTokens(cdc, appState, opts, nodeAddrs, "node0", genAccounts, genBalances)
+	require.NoError(t, err)
+
+	var tokenGenState tokentypes.GenesisState
+	cdc.MustUnmarshalJSON(appState[tokentypes.ModuleName], &tokenGenState)
+	require.NoError(t, tokentypes.ValidateGenesis(tokenGenState))
+
+	owners := make(map[string]string)
+	for _, token := range tokenGenState.Tokens {
+		owners[token.Symbol] = token.Owner
+	}
+	// the owner defaults to the first node
+	require.Equal(t, node0.String(), owners["btc"])
+	require.Equal(t, node1.String(), owners["eth"])
+	require.Equal(t, other.String(), owners["usdt"])
+
+	// the owners are given the initial supply in the min unit, initial_supply * 10^scale
+	balances := make(map[string]sdk.Coins)
+	for _, balance := range genBalances {
+		balances[balance.Address] = balance.Coins
+	}
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin("satoshi", sdk.NewInt(2100000000000000)), sdk.NewInt64Coin("stake", 10)), balances[node0.String()])
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("gwei", 1000000000000), sdk.NewInt64Coin("stake", 10)), balances[node1.String()])
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uusdt", 5000000)), balances[other.String()])
+
+	// the account of an owner who is not a node is added
+	require.Len(t, genAccounts, 3)
+	require.True(t, authtypes.GenesisAccounts(genAccounts).Contains(other))
+
+	// a token issued twice is rejected
+	_, _, err = issueTestnetTokens(cdc, appState, testnetGenesisOptions{tokens: opts.tokens[:1]}, nodeAddrs, "node0", genAccounts, genBalances)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already issued")
+}
+
+func mustAccAddress(t *testing.T, addr string) sdk.AccAddress {
+	accAddr, err := sdk.AccAddressFromBech32(addr)
+	require.NoError(t, err)
+	return accAddr
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/types/query"
+
+	tokentypes "github.com/irisnet/irismod/modules/token/types"
+)
+
+const (
+	flagTokenCacheTTL = "token-cache-ttl"
+
+	// tokenCacheFile is the file under the config dir of the client home caching the token metadata
+	tokenCacheFile = "token_cache.json"
+
+	defaultTokenCacheTTL = 24 * time.Hour
+)
+
+// cachedToken is the token metadata needed for the unit conversion
+type cachedToken struct {
+	Symbol    string    `json:"symbol"`
+	Name      string    `json:"name"`
+	Scale     uint32    `json:"scale"`
+	MinUnit   string    `json:"min_unit"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+func newCachedToken(token tokentypes.TokenI, fetchedAt time.Time) cachedToken {
+	return cachedToken{
+		Symbol:    token.GetSymbol(),
+		Name:      token.GetName(),
+		Scale:     token.GetScale(),
+		MinUnit:   token.GetMinUnit(),
+		FetchedAt: fetchedAt,
+	}
+}
+
+func (t cachedToken) token() tokentypes.TokenI {
+	return &tokentypes.Token{
+		Symbol:  t.Symbol,
+		Name:    t.Name,
+		Scale:   t.Scale,
+		MinUnit: t.MinUnit,
+	}
+}
+
+// tokenCache is the on-disk cache of the token metadata, keyed by the token symbols
+type tokenCache struct {
+	file   string
+	Tokens map[string]cachedToken `json:"tokens"`
+}
+
+// loadTokenCache returns the token cache under the home, which is empty if it does not exist.
+// The empty cache is returned along with the error if the cache file is corrupt.
+func loadTokenCache(home string) (*tokenCache, error) {
+	cache := &tokenCache{
+		file:   filepath.Join(home, "config", tokenCacheFile),
+		Tokens: map[string]cachedToken{},
+	}
+
+	bz, err := ioutil.ReadFile(cache.file)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bz, cache); err != nil {
+		cache.Tokens = map[string]cachedToken{}
+		return cache, fmt.Errorf("invalid token cache %s: %s", cache.file, err)
+	}
+	if cache.Tokens == nil {
+		cache.Tokens = map[string]cachedToken{}
+	}
+	return cache, nil
+}
+
+// get returns the cached token by its symbol or min unit
+func (c *tokenCache) get(denom string) (cachedToken, bool) {
+	if t, ok := c.Tokens[denom]; ok {
+		return t, true
+	}
+	for _, t := range c.Tokens {
+		if t.MinUnit == denom {
+			return t, true
+		}
+	}
+	return cachedToken{}, false
+}
+
+func (c *tokenCache) set(t cachedToken) {
+	c.Tokens[t.Symbol] = t
+}
+
+func (c *tokenCache) save() error {
+	bz, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.file, bz, 0644)
+}
+
+// tokensCmd returns the commands to manage the token metadata cache of the converter
+func tokensCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        "tokens",
+		Short:                      "Manage the token metadata cache used for the unit conversion",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(tokensSyncCmd())
+	return cmd
+}
+
+func tokensSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Fetch the metadata of all the tokens into the local cache",
+		Long: fmt.Sprintf(`Fetch the metadata of all the tokens from the node into the token cache under the home,
+so that the coins can be converted between the main and the min units without querying the node,
+e.g. on an air-gapped machine signing with --%s, --%s or --%s.`, flags.FlagGenerateOnly, flags.FlagOffline, flagConvertOffline),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			cache, err := loadTokenCache(clientCtx.HomeDir)
+			if err != nil {
+				if cache == nil {
+					return err
+				}
+				cmd.PrintErrf("WARNING: %s, the token cache is rebuilt\n", err)
+			}
+
+			queryClient := tokentypes.NewQueryClient(clientCtx)
+			now := time.Now().UTC()
+			synced := 0
+			var nextKey []byte
+			for {
+				res, err := queryClient.Tokens(context.Background(), &tokentypes.QueryTokensRequest{
+					Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+				})
+				if err != nil {
+					return err
+				}
+
+				for _, any := range res.Tokens {
+					var token tokentypes.TokenI
+					if err := clientCtx.InterfaceRegistry.UnpackAny(any, &token); err != nil {
+						return err
+					}
+					cache.set(newCachedToken(token, now))
+					synced++
+				}
+
+				if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+					break
+				}
+				nextKey = res.Pagination.NextKey
+			}
+
+			if err := cache.save(); err != nil {
+				return err
+			}
+			cmd.PrintErrf("Synced %d tokens to %s\n", synced, cache.file)
+			return nil
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
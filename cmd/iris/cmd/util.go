@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/olebedev/config"
 	"github.com/spf13/cobra"
@@ -18,74 +22,77 @@ import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
 
 	tokentypes "github.com/irisnet/irismod/modules/token/types"
+
+	converterspec "github.com/irisnet/irishub/converter"
 )
 
 const (
-	formatJSON     = "json"
-	cmdScopeGlobal = "global"
-	filedTypeMap   = "map"
-	filedTypeArray = "array"
-)
+	flagDisplayUnits   = "display-units"
+	flagConvertOffline = "convert-offline"
 
-var (
-	converter = NewConverter().
-			registerGlobalFlag("fees").
-			registerGlobalFlag("amount").
-			registerGlobalFlag("deposit").
-			registerGlobalFlag("service-fee-cap").
-			registerCmdWithArgs("bank", "send", 2).
-			registerCmdWithArgs("staking", "delegate", 1).
-			registerCmdWithArgs("staking", "redelegate", 2).
-			registerCmdWithArgs("staking", "unbond", 1).
-			registerCmdWithArgs("distribution", "fund-community-pool", 0).
-			registerCmdWithArgs("gov", "deposit", 1).
-			registerCmdForResponse("bank", "balances", "balances", filedTypeArray).
-			registerCmdForResponse("bank", "total", "supply", filedTypeArray).
-			registerCmdForResponse("gov", "params", "deposit_params.min_deposit", filedTypeArray).
-			registerCmdForResponse("distribution", "validator-outstanding-rewards", "rewards", filedTypeArray).
-			registerCmdForResponses("distribution", "rewards",
-			field{name: "total", typ: filedTypeArray},
-			field{name: "rewards.*.reward", typ: filedTypeArray},
-			field{name: "rewards", typ: filedTypeArray}).
-		registerCmdForResponse("token", "total-burn", "burned_coins", filedTypeArray)
+	formatJSON = "json"
 
-	rescueStdout = os.Stdout
+	// converterSpecsFile is the file under the config dir of the client home with the extra conversion specs
+	converterSpecsFile = "converter.json"
 )
 
-type (
-	field struct {
-		name  string
-		index int
-		typ   string
-	}
+var (
+	converter = NewConverter()
 
-	command struct {
-		parentCmd string
-		fields    map[string]field
+	// coinAttributeKeys are the keys of the tx event attributes holding coins
+	coinAttributeKeys = map[string]bool{
+		"amount":      true,
+		"fee":         true,
+		"deposit":     true,
+		"rewards":     true,
+		"commission":  true,
+		"service_fee": true,
 	}
+
+	rescueStdout = os.Stdout
+
+	// errTokenNotCached is returned when a token is not in the token cache in the offline mode
+	errTokenNotCached = errors.New("not in the token cache")
 )
 
-func (c command) append(name, typ string, index int) command {
-	c.fields[name] = field{
-		name:  name,
-		index: index,
-		typ:   typ,
-	}
-	return c
+// command holds the conversion rules of a command
+type command struct {
+	args  []int
+	flags map[string]bool
+	paths []string
 }
 
 type coinConverter struct {
-	cmds   map[string]command
+	cmds   map[string]*command
+	global map[string]bool
 	tokens map[string]tokentypes.TokenI
 	r, w   *os.File
+
+	// cache is the on-disk token metadata cache, whose entries older than ttl are refreshed
+	// from the node unless offline is set
+	cache   *tokenCache
+	ttl     time.Duration
+	offline bool
 }
 
+// outputKind defines the kind of the command output to be converted to the main units
+type outputKind int
+
+const (
+	outputNone outputKind = iota
+	outputQueryYAML
+	outputQueryJSON
+	outputTx
+)
+
 // NewConverter return a instance of coinConverter
 func NewConverter() *coinConverter {
 	return &coinConverter{
-		cmds:   make(map[string]command),
+		cmds:   make(map[string]*command),
+		global: make(map[string]bool),
 		tokens: make(map[string]tokentypes.TokenI),
 	}
 }
@@ -94,133 +101,129 @@ func (it *coinConverter) key(parentCmd, cmd string) string {
 	return fmt.Sprintf("%s/%s", parentCmd, cmd)
 }
 
-func (it *coinConverter) registerCmdWithArgs(parentCmd, cmd string, argsIdx int) *coinConverter {
-	commands, ok := it.cmds[cmd]
-	if !ok {
-		commands = command{
-			parentCmd: parentCmd,
-			fields:    map[string]field{},
-		}
+func (it *coinConverter) cmdKey(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return it.key("", cmd.Name())
 	}
-	commands = commands.append("ARGS", "", argsIdx)
-	it.cmds[cmd] = commands
-	return it
+	return it.key(cmd.Parent().Name(), cmd.Name())
 }
 
-func (it *coinConverter) registerGlobalFlag(flagNm string) *coinConverter {
-	commands, ok := it.cmds[cmdScopeGlobal]
-	if !ok {
-		commands = command{
-			parentCmd: cmdScopeGlobal,
-			fields:    map[string]field{},
+// register adds the conversion rules of the specs
+func (it *coinConverter) register(specs ...converterspec.Spec) *coinConverter {
+	for _, spec := range specs {
+		if spec.IsGlobal() {
+			for _, flag := range spec.Flags {
+				it.global[flag] = true
+			}
+			continue
 		}
-	}
-	commands = commands.append(flagNm, "", -1)
-	it.cmds[cmdScopeGlobal] = commands
-	return it
-}
 
-func (it *coinConverter) registerCmdWithFlag(parentCmd, cmd, flagNm string) *coinConverter {
-	commands, ok := it.cmds[cmd]
-	if !ok {
-		commands = command{
-			parentCmd: parentCmd,
-			fields:    map[string]field{},
+		key := it.key(spec.Module, spec.Command)
+		c, ok := it.cmds[key]
+		if !ok {
+			c = &command{flags: map[string]bool{}}
+			it.cmds[key] = c
 		}
-	}
-	commands = commands.append(flagNm, "", -1)
-	it.cmds[cmd] = commands
-	return it
-}
-
-func (it *coinConverter) registerCmdForResponse(parentCmd, cmd, jsonPath, typ string) *coinConverter {
-	commands, ok := it.cmds[cmd]
-	if !ok {
-		commands = command{
-			parentCmd: parentCmd,
-			fields:    map[string]field{},
+		c.args = append(c.args, spec.Args...)
+		c.paths = append(c.paths, spec.Paths...)
+		for _, flag := range spec.Flags {
+			c.flags[flag] = true
 		}
 	}
-
-	commands = commands.append(jsonPath, typ, -1)
-
-	it.cmds[cmd] = commands
 	return it
 }
 
-func (it *coinConverter) registerCmdForResponses(parentCmd, cmd string, fields ...field) *coinConverter {
-	commands, ok := it.cmds[cmd]
-	if !ok {
-		commands = command{
-			parentCmd: parentCmd,
-			fields:    map[string]field{},
-		}
-	}
+// loadSpecs registers the conversion specs registered by the modules, as well as the
+// ones in the converter.json file under the config dir of the client home
+func (it *coinConverter) loadSpecs(cmd *cobra.Command) error {
+	it.register(converterspec.Specs()...)
 
-	for _, field := range fields {
-		commands = commands.append(field.name, field.typ, -1)
+	home := client.GetClientContextFromCmd(cmd).HomeDir
+	if home == "" {
+		return nil
 	}
-
-	it.cmds[cmd] = commands
-	return it
+	specs, err := converterspec.LoadFile(filepath.Join(home, "config", converterSpecsFile))
+	if err != nil {
+		return err
+	}
+	it.register(specs...)
+	return nil
 }
 
-func (it coinConverter) hasFromFlag(cmdNm, flagNm string) bool {
-	if cmd, ok := it.cmds[cmdNm]; ok {
-		if _, ok = cmd.fields[flagNm]; ok {
-			return ok
-		}
+func (it coinConverter) hasFromFlag(cmd *cobra.Command, flagNm string) bool {
+	if c, ok := it.cmds[it.cmdKey(cmd)]; ok && c.flags[flagNm] {
+		return true
 	}
-	cmd := it.cmds[cmdScopeGlobal]
-	_, ok := cmd.fields[flagNm]
-	return ok
+	return it.global[flagNm]
 }
 
-func (it coinConverter) getFromArgs(cmdNm string) (field, bool) {
-	cmd, ok := it.cmds[cmdNm]
-	if !ok {
-		return field{}, false
-	}
-	f := cmd.fields["ARGS"]
-	if f.index < 0 {
-		return f, false
+func (it coinConverter) getFromArgs(cmd *cobra.Command) []int {
+	if c, ok := it.cmds[it.cmdKey(cmd)]; ok {
+		return c.args
 	}
-	return cmd.fields["ARGS"], true
+	return nil
 }
 
-func (it coinConverter) getFields(cmdNm string) map[string]field {
-	cmd, ok := it.cmds[cmdNm]
-	if !ok {
-		return map[string]field{}
+func (it coinConverter) getPaths(cmd *cobra.Command) []string {
+	if c, ok := it.cmds[it.cmdKey(cmd)]; ok {
+		return c.paths
 	}
-	return cmd.fields
+	return nil
 }
 
-func (it *coinConverter) handlePreRun(cmd *cobra.Command, args []string) {
-	if b, _ := cmd.Flags().GetBool(flags.FlagGenerateOnly); b {
-		return
+func (it *coinConverter) handlePreRun(cmd *cobra.Command, args []string) error {
+	// invalid conversion specs only fail the commands whose coins are converted
+	if err := it.loadSpecs(cmd); err != nil {
+		if isConvertingCommand(cmd) {
+			return err
+		}
+		cmd.PrintErrf("WARNING: %s\n", err)
+	}
+
+	// the tx generated or signed offline is converted with the cached token metadata only
+	generateOnly, _ := cmd.Flags().GetBool(flags.FlagGenerateOnly)
+	txOffline, _ := cmd.Flags().GetBool(flags.FlagOffline)
+	convertOffline, _ := cmd.Flags().GetBool(flagConvertOffline)
+	it.offline = convertOffline || txOffline || generateOnly
+	it.ttl, _ = cmd.Flags().GetDuration(flagTokenCacheTTL)
+	if home := client.GetClientContextFromCmd(cmd).HomeDir; home != "" {
+		// the token cache is best effort, a corrupt one is taken as empty
+		cache, err := loadTokenCache(home)
+		if err != nil {
+			cmd.PrintErrf("WARNING: %s\n", err)
+		}
+		it.cache = cache
 	}
-	cmdNm := cmd.Name()
+
 	//handle flag
+	var err error
 	cmd.Flags().Visit(func(flag *pflag.Flag) {
 		if flag.Changed {
 			viper.SetDefault(flag.Name, flag.Value)
 		}
-		it.parseFlags(cmd, flag, cmdNm)
+		if flagErr := it.parseFlags(cmd, flag); flagErr != nil && err == nil {
+			err = flagErr
+		}
 	})
+	if err != nil {
+		return err
+	}
 
 	//handle field
-	it.parseArgs(cmd, args[:])
+	if err := it.parseArgs(cmd, args[:]); err != nil {
+		return err
+	}
 
-	if !it.isOutputYAML(cmd) {
-		return
+	if generateOnly || it.outputKind(cmd) == outputNone {
+		return nil
 	}
 	it.r, it.w, _ = os.Pipe()
 	os.Stdout = it.w
+	return nil
 }
 
 func (it *coinConverter) handlePostRun(cmd *cobra.Command) {
-	if !it.isOutputYAML(cmd) {
+	if it.r == nil {
 		return
 	}
 	if it.w != nil {
@@ -228,24 +231,69 @@ func (it *coinConverter) handlePostRun(cmd *cobra.Command) {
 	}
 	out, _ := ioutil.ReadAll(it.r)
 	os.Stdout = rescueStdout
-	fmt.Println(it.parseYAML(cmd, out))
+
+	switch it.outputKind(cmd) {
+	case outputQueryYAML:
+		fmt.Println(it.parseYAML(cmd, out))
+	case outputQueryJSON:
+		fmt.Println(it.parseJSON(cmd, out))
+	case outputTx:
+		fmt.Println(it.parseTxResponse(cmd, out))
+	default:
+		fmt.Print(string(out))
+	}
 }
 
-func (it coinConverter) parseFlags(cmd *cobra.Command, flag *pflag.Flag, cmdNm string) {
-	if it.hasFromFlag(cmdNm, flag.Name) {
+// outputKind returns the kind of the output to be converted. The YAML query output is always
+// converted, while the JSON query output and the tx response are only converted with --display-units.
+func (it *coinConverter) outputKind(cmd *cobra.Command) outputKind {
+	if it.isOutputYAML(cmd) {
+		return outputQueryYAML
+	}
+	if displayUnits, _ := cmd.Flags().GetBool(flagDisplayUnits); !displayUnits {
+		return outputNone
+	}
+	if it.isOutputJSON(cmd) && strings.Contains(cmd.CommandPath(), queryCommand().CommandPath()) {
+		return outputQueryJSON
+	}
+	if isSubCommandOf(cmd, txCommand().Name()) {
+		return outputTx
+	}
+	return outputNone
+}
+
+// parseFlags converts the coins of the flag to the min units. The coins which can not be
+// parsed are left to the command, while the tokens missing from the cache in the offline
+// mode fail the command.
+func (it coinConverter) parseFlags(cmd *cobra.Command, flag *pflag.Flag) error {
+	if it.hasFromFlag(cmd, flag.Name) {
 		srcCoinStr := flag.Value.String()
-		if res, err := it.convertCoins(cmd, srcCoinStr); err == nil {
+		res, err := it.convertCoins(cmd, srcCoinStr)
+		if errors.Is(err, errTokenNotCached) {
+			return err
+		}
+		if err == nil {
 			_ = flag.Value.Set(res)
 		}
 	}
+	return nil
 }
 
-func (it coinConverter) parseArgs(cmd *cobra.Command, args []string) {
-	if field, ok := it.getFromArgs(cmd.Name()); ok && len(args) > 0 {
-		if res, err := it.convertCoins(cmd, args[field.index]); err == nil {
-			args[field.index] = res
+// parseArgs converts the coins of the args to the min units, like parseFlags
+func (it coinConverter) parseArgs(cmd *cobra.Command, args []string) error {
+	for _, idx := range it.getFromArgs(cmd) {
+		if idx >= len(args) {
+			continue
+		}
+		res, err := it.convertCoins(cmd, args[idx])
+		if errors.Is(err, errTokenNotCached) {
+			return err
+		}
+		if err == nil {
+			args[idx] = res
 		}
 	}
+	return nil
 }
 
 func (it coinConverter) parseYAML(cmd *cobra.Command, in []byte) string {
@@ -254,24 +302,80 @@ func (it coinConverter) parseYAML(cmd *cobra.Command, in []byte) string {
 		return string(in)
 	}
 
-	fields := it.getFields(cmd.Name())
-	for path, field := range fields {
-		for _, p := range it.resolvePath(cfg, path) {
-			switch field.typ {
-			case filedTypeArray:
-				it.handleList(cmd, cfg, p)
-			case filedTypeMap:
-				it.handleMap(cmd, cfg, p)
+	it.convertFields(cmd, cfg)
+	s, err := config.RenderYaml(cfg.Root)
+	if err != nil {
+		return string(in)
+	}
+	return s
+}
+
+func (it coinConverter) parseJSON(cmd *cobra.Command, in []byte) string {
+	cfg, err := parseJSONBytes(in)
+	if err != nil {
+		return string(in)
+	}
+
+	it.convertFields(cmd, cfg)
+	s, err := config.RenderJson(cfg.Root)
+	if err != nil {
+		return string(in)
+	}
+	return s
+}
+
+// parseTxResponse converts the coins of the event attributes in the tx response, which
+// is either in JSON or in YAML
+func (it coinConverter) parseTxResponse(cmd *cobra.Command, in []byte) string {
+	render := config.RenderJson
+	cfg, err := parseJSONBytes(in)
+	if err != nil {
+		if cfg, err = config.ParseYamlBytes(in); err != nil {
+			return string(in)
+		}
+		render = config.RenderYaml
+	}
+
+	for _, p := range it.resolvePath(cfg, "logs.*.events.*.attributes") {
+		attrs, err := cfg.List(p)
+		if err != nil {
+			continue
+		}
+		for i := range attrs {
+			attrPath := fmt.Sprintf("%s.%d", p, i)
+			if key, err := cfg.String(attrPath + ".key"); err != nil || !coinAttributeKeys[key] {
+				continue
+			}
+			value, err := cfg.String(attrPath + ".value")
+			if err != nil {
+				continue
+			}
+			if res, err := it.convertCoinsToMain(cmd, value); err == nil {
+				_ = cfg.Set(attrPath+".value", res)
 			}
 		}
 	}
-	s, err := config.RenderYaml(cfg.Root)
+
+	s, err := render(cfg.Root)
 	if err != nil {
 		return string(in)
 	}
 	return s
 }
 
+func (it coinConverter) convertFields(cmd *cobra.Command, cfg *config.Config) {
+	for _, path := range it.getPaths(cmd) {
+		for _, p := range it.resolvePath(cfg, path) {
+			// the path holds either a list of coins or a single coin
+			if _, err := cfg.List(p); err == nil {
+				it.handleList(cmd, cfg, p)
+				continue
+			}
+			it.handleMap(cmd, cfg, p)
+		}
+	}
+}
+
 func (it coinConverter) resolvePath(cfg *config.Config, path string) (paths []string) {
 	subPaths := strings.SplitN(path, "*", 2)
 	if len(subPaths) == 1 {
@@ -294,11 +398,55 @@ func (it coinConverter) resolvePath(cfg *config.Config, path string) (paths []st
 	return paths
 }
 
+// queryToken returns the token from the memory, the fresh entries of the token cache or the node,
+// falling back to the stale entries of the token cache if the node is unreachable. Only the token
+// cache is used in the offline mode.
 func (it *coinConverter) queryToken(cmd *cobra.Command, denom string) (ft tokentypes.TokenI, err error) {
 	if ft, ok := it.tokens[denom]; ok {
 		return ft, nil
 	}
 
+	var cached *cachedToken
+	if it.cache != nil {
+		if t, ok := it.cache.get(denom); ok {
+			cached = &t
+		}
+	}
+	if cached != nil && (it.offline || time.Since(cached.FetchedAt) < it.ttl) {
+		it.tokens[denom] = cached.token()
+		return it.tokens[denom], nil
+	}
+	if it.offline {
+		// the native token is known without the cache
+		if native := tokentypes.GetNativeToken(); denom == native.Symbol || denom == native.MinUnit {
+			it.tokens[denom] = &native
+			return it.tokens[denom], nil
+		}
+		if err := tokentypes.ValidateSymbol(denom); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("token %s %w, run `%s tokens sync`", denom, errTokenNotCached, version.AppName)
+	}
+
+	evi, err := it.fetchToken(cmd, denom)
+	if err != nil {
+		if cached != nil {
+			it.tokens[denom] = cached.token()
+			return it.tokens[denom], nil
+		}
+		return nil, err
+	}
+
+	if it.cache != nil {
+		it.cache.set(newCachedToken(evi, time.Now().UTC()))
+		// the cache is best effort, the conversion does not fail if it can not be written
+		_ = it.cache.save()
+	}
+	it.tokens[denom] = evi
+	return evi, nil
+}
+
+func (it *coinConverter) fetchToken(cmd *cobra.Command, denom string) (tokentypes.TokenI, error) {
 	clientCtx, err := client.GetClientQueryContext(cmd)
 	if err != nil {
 		return nil, err
@@ -322,8 +470,6 @@ func (it *coinConverter) queryToken(cmd *cobra.Command, denom string) (ft tokent
 	if err != nil {
 		return nil, err
 	}
-
-	it.tokens[denom] = evi
 	return evi, nil
 }
 
@@ -340,6 +486,11 @@ func (it *coinConverter) isOutputYAML(cmd *cobra.Command) bool {
 	return true
 }
 
+func (it *coinConverter) isOutputJSON(cmd *cobra.Command) bool {
+	output, err := cmd.Flags().GetString(cli.OutputFlag)
+	return viper.GetString(cli.OutputFlag) == formatJSON || (err == nil && output == formatJSON)
+}
+
 func (it *coinConverter) handleList(cmd *cobra.Command, cfg *config.Config, path string) {
 	list, err := cfg.List(path)
 	if err != nil {
@@ -381,16 +532,38 @@ func (it *coinConverter) convertCoins(cmd *cobra.Command, coinsStr string) (dstC
 	}
 	dstCoins := sdk.Coins{}
 	for _, coin := range cs {
-		if c, err := it.convertToMinCoin(cmd, coin); err == nil {
+		c, err := it.convertToMinCoin(cmd, coin)
+		if err == nil {
 			dstCoins = append(dstCoins, c)
 			continue
 		}
-		c, _ := coin.TruncateDecimal()
+		// the coins are not truncated silently when the token is missing offline
+		if errors.Is(err, errTokenNotCached) {
+			return coinsStr, err
+		}
+		c, _ = coin.TruncateDecimal()
 		dstCoins = append(dstCoins, c)
 	}
 	return dstCoins.String(), nil
 }
 
+// convertCoinsToMain converts the coins in the min units to the coins in the main units,
+// leaving the coins of the unknown tokens untouched
+func (it *coinConverter) convertCoinsToMain(cmd *cobra.Command, coinsStr string) (string, error) {
+	cs, err := sdk.ParseCoinsNormalized(coinsStr)
+	if err != nil {
+		return coinsStr, err
+	}
+	dstCoins := make([]string, len(cs))
+	for i, coin := range cs {
+		dstCoins[i] = coin.String()
+		if c, err := it.convertToMainCoin(cmd, coin); err == nil {
+			dstCoins[i] = c.String()
+		}
+	}
+	return strings.Join(dstCoins, ","), nil
+}
+
 func (it *coinConverter) convertToMinCoin(cmd *cobra.Command, srcCoin sdk.DecCoin) (coin sdk.Coin, err error) {
 	ft, err := it.queryToken(cmd, srcCoin.Denom)
 	if err != nil {
@@ -402,6 +575,9 @@ func (it *coinConverter) convertToMinCoin(cmd *cobra.Command, srcCoin sdk.DecCoi
 func (it *coinConverter) convertToMainCoin(cmd *cobra.Command, srcCoin sdk.Coin) (coin sdk.DecCoin, err error) {
 	ft, err := it.queryToken(cmd, srcCoin.Denom)
 	if err != nil {
+		if errors.Is(err, errTokenNotCached) {
+			cmd.PrintErrf("WARNING: %s to convert it offline\n", err)
+		}
 		return coin, err
 	}
 	return ft.ToMainCoin(srcCoin)
@@ -416,3 +592,35 @@ func (it *coinConverter) parseCoins(srcCoinsStr string) (sdk.DecCoins, error) {
 	}
 	return sdk.DecCoins{}, fmt.Errorf("parsed decimal coins are invalid: %s", srcCoinsStr)
 }
+
+// parseJSONBytes parses the JSON output, keeping the numbers as they are
+func parseJSONBytes(in []byte) (*config.Config, error) {
+	var root interface{}
+	decoder := json.NewDecoder(bytes.NewReader(in))
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	switch root.(type) {
+	case map[string]interface{}, []interface{}:
+		return &config.Config{Root: root}, nil
+	default:
+		return nil, fmt.Errorf("invalid JSON output")
+	}
+}
+
+// isConvertingCommand returns true if the coins of the command are converted, i.e. it is
+// a query or tx command
+func isConvertingCommand(cmd *cobra.Command) bool {
+	return isSubCommandOf(cmd, queryCommand().Name()) || isSubCommandOf(cmd, txCommand().Name())
+}
+
+// isSubCommandOf returns true if the command is under the given child command of the root command
+func isSubCommandOf(cmd *cobra.Command, name string) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		if !cmd.Parent().HasParent() {
+			return cmd.Name() == name
+		}
+	}
+	return false
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/version"
+
+	tokentypes "github.com/irisnet/irismod/modules/token/types"
+
+	converterspec "github.com/irisnet/irishub/converter"
+)
+
+// newTestCommands returns the query, tx and keys leaf commands under a root command with the
+// output flags, keyed by their parent and name
+func newTestCommands() map[string]*cobra.Command {
+	rootCmd := &cobra.Command{Use: "iris"}
+	rootCmd.PersistentFlags().StringP(cli.OutputFlag, "o", "text", "")
+	rootCmd.PersistentFlags().Bool(flagDisplayUnits, false, "")
+	rootCmd.PersistentFlags().Bool(flagConvertOffline, false, "")
+
+	cmds := make(map[string]*cobra.Command)
+	for parent, children := range map[string][]string{
+		"query": {"bank", "balances"},
+		"tx":    {"bank", "send"},
+		"keys":  {"list"},
+	} {
+		cmd := &cobra.Command{Use: parent}
+		rootCmd.AddCommand(cmd)
+		for _, name := range children {
+			child := &cobra.Command{Use: name}
+			cmd.AddCommand(child)
+			cmd = child
+		}
+		if parent == "tx" {
+			cmd.Flags().Bool(flags.FlagOffline, false, "")
+			cmd.Flags().Bool(flags.FlagGenerateOnly, false, "")
+			cmd.Flags().String(flags.FlagFees, "", "")
+		}
+		cmd.SetErr(ioutil.Discard)
+		cmds[parent] = cmd
+	}
+	return cmds
+}
+
+// newTestConverter returns an offline converter knowing the iris and btc tokens
+func newTestConverter() *coinConverter {
+	it := NewConverter()
+	it.offline = true
+	for _, token := range []tokentypes.Token{
+		tokentypes.NewToken("iris", "Irishub staking token", "uiris", 6, 2000000000, 0, true, nil),
+		tokentypes.NewToken("btc", "Bitcoin", "satoshi", 8, 21000000, 0, false, nil),
+	} {
+		token := token
+		it.tokens[token.Symbol] = &token
+		it.tokens[token.MinUnit] = &token
+	}
+	return it
+}
+
+func TestOutputKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		expected outputKind
+	}{
+		{"query text", "query", nil, outputQueryYAML},
+		{"query text with display units", "query", []string{"--" + flagDisplayUnits}, outputQueryYAML},
+		{"query json", "query", []string{"-o", "json"}, outputNone},
+		{"query json with display units", "query", []string{"-o", "json", "--" + flagDisplayUnits}, outputQueryJSON},
+		{"tx", "tx", nil, outputNone},
+		{"tx with display units", "tx", []string{"--" + flagDisplayUnits}, outputTx},
+		{"tx json with display units", "tx", []string{"-o", "json", "--" + flagDisplayUnits}, outputTx},
+		{"keys json with display units", "keys", []string{"-o", "json", "--" + flagDisplayUnits}, outputNone},
+	}
+
+	for _, tc := range tests {
+		cmd := newTestCommands()[tc.cmd]
+		require.NoError(t, cmd.ParseFlags(tc.args), tc.name)
+		require.Equal(t, tc.expected, NewConverter().outputKind(cmd), tc.name)
+	}
+}
+
+func TestConvertCoinsToMain(t *testing.T) {
+	cmd := newTestCommands()["query"]
+
+	tests := []struct {
+		name     string
+		coins    string
+		expected string
+		expError bool
+	}{
+		{"min unit", "1500000uiris", "1.500000000000000000iris", false},
+		{"several tokens", "1500000uiris,150000000satoshi", "1.500000000000000000btc,1.500000000000000000iris", false},
+		{"unknown token left untouched", "1500000uiris,10unknown", "1.500000000000000000iris,10unknown", false},
+		{"main unit", "2iris", "2.000000000000000000iris", false},
+		{"empty", "", "", false},
+		{"invalid coins", "1.5.uiris", "1.5.uiris", true},
+	}
+
+	for _, tc := range tests {
+		res, err := newTestConverter().convertCoinsToMain(cmd, tc.coins)
+		if tc.expError {
+			require.Error(t, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+		}
+		require.Equal(t, tc.expected, res, tc.name)
+	}
+}
+
+// requireEqualJSON asserts that the JSON documents are equal regardless of their formatting
+func requireEqualJSON(t *testing.T, expected, actual, msg string) {
+	var exp, act interface{}
+	require.NoError(t, json.Unmarshal([]byte(expected), &exp), msg)
+	require.NoError(t, json.Unmarshal([]byte(actual), &act), msg)
+	require.Equal(t, exp, act, msg)
+}
+
+func TestParseTxResponse(t *testing.T) {
+	cmd := newTestCommands()["tx"]
+
+	const jsonTxResponse = `{"height":"10","txhash":"ABCD","logs":[{"msg_index":0,"events":[
+{"type":"message","attributes":[{"key":"action","value":"send"},{"key":"sender","value":"iaa1sender"}]},
+{"type":"transfer","attributes":[{"key":"recipient","value":"iaa1recipient"},{"key":"amount","value":"1500000uiris,10unknown"}]}
+]},{"msg_index":1,"events":[
+{"type":"withdraw_rewards","attributes":[{"key":"amount","value":"250000000satoshi"},{"key":"validator","value":"iva1validator"}]}
+]}],"gas_wanted":"200000","gas_used":"100000"}`
+
+	tests := []struct {
+		name     string
+		in       string
+		expected string
+	}{
+		{
+			"json tx response",
+			jsonTxResponse,
+			`{"height":"10","txhash":"ABCD","logs":[{"msg_index":0,"events":[
+{"type":"message","attributes":[{"key":"action","value":"send"},{"key":"sender","value":"iaa1sender"}]},
+{"type":"transfer","attributes":[{"key":"recipient","value":"iaa1recipient"},{"key":"amount","value":"1.500000000000000000iris,10unknown"}]}
+]},{"msg_index":1,"events":[
+{"type":"withdraw_rewards","attributes":[{"key":"amount","value":"2.500000000000000000btc"},{"key":"validator","value":"iva1validator"}]}
+]}],"gas_wanted":"200000","gas_used":"100000"}`,
+		},
+		{
+			"json tx response without logs",
+			`{"height":"0","txhash":"ABCD","code":13,"raw_log":"insufficient fee","logs":[]}`,
+			`{"height":"0","txhash":"ABCD","code":13,"raw_log":"insufficient fee","logs":[]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		requireEqualJSON(t, tc.expected, newTestConverter().parseTxResponse(cmd, []byte(tc.in)), tc.name)
+	}
+
+	// the YAML tx response is rendered as YAML
+	yamlTxResponse := `height: "10"
+logs:
+- events:
+  - attributes:
+    - key: fee
+      value: 300000uiris
+    - key: fee_payer
+      value: iaa1sender
+    type: tx
+  msg_index: 0
+txhash: ABCD
+`
+	out := newTestConverter().parseTxResponse(cmd, []byte(yamlTxResponse))
+	cfg, err := config.ParseYamlBytes([]byte(out))
+	require.NoError(t, err)
+	fee, err := cfg.String("logs.0.events.0.attributes.0.value")
+	require.NoError(t, err)
+	require.Equal(t, "0.300000000000000000iris", fee)
+	payer, err := cfg.String("logs.0.events.0.attributes.1.value")
+	require.NoError(t, err)
+	require.Equal(t, "iaa1sender", payer)
+
+	// the output which can not be parsed is left as is
+	require.Equal(t, `{"txhash":`, newTestConverter().parseTxResponse(cmd, []byte(`{"txhash":`)))
+}
+
+func TestParseJSON(t *testing.T) {
+	cmd := newTestCommands()["query"]
+	it := newTestConverter().register(converterspec.Spec{Module: "bank", Command: "balances", Paths: []string{"balances", "rewards.*.reward"}})
+
+	in := `{"balances":[{"denom":"uiris","amount":"1500000"},{"denom":"unknown","amount":"10"}],
+"rewards":[{"validator_address":"iva1validator","reward":[{"denom":"satoshi","amount":"250000000.500000000000000000"}]}],
+"pagination":{"next_key":null,"total":"2"}}`
+	expected := `{"balances":[{"denom":"iris","amount":"1.500000000000000000"},{"denom":"unknown","amount":"10"}],
+"rewards":[{"validator_address":"iva1validator","reward":[{"denom":"btc","amount":"2.500000000000000000"}]}],
+"pagination":{"next_key":null,"total":"2"}}`
+	requireEqualJSON(t, expected, it.parseJSON(cmd, []byte(in)), "json query output")
+
+	// the numbers of the output are kept as they are
+	require.Contains(t, it.parseJSON(cmd, []byte(`{"height":12345678901234567890}`)), "12345678901234567890")
+}
+
+// runPreRun runs the converter pre-run of the command with the client home, returning the args
+// passed to the command and the error output
+func runPreRun(t *testing.T, it *coinConverter, cmdName, home string, args ...string) ([]string, string, error) {
+	cmd := newTestCommands()[cmdName]
+	errOut := &bytes.Buffer{}
+	cmd.SetErr(errOut)
+	cmd.Root().SetArgs(append(strings.Split(cmd.CommandPath(), " ")[1:], args...))
+	cmd.Root().PersistentPreRunE = it.handlePreRun
+	var runArgs []string
+	cmd.Run = func(_ *cobra.Command, args []string) { runArgs = args }
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+
+	ctx := context.WithValue(context.Background(), client.ClientContextKey, &client.Context{HomeDir: home})
+	err := cmd.Root().ExecuteContext(ctx)
+	return runArgs, errOut.String(), err
+}
+
+func TestHandlePreRunInvalidSpecs(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(home, "config"), 0755))
+	writeTestFile(t, filepath.Join(home, "config"), converterSpecsFile, `[{"module":"bank"`)
+
+	// only the commands converting coins fail
+	_, errOut, err := runPreRun(t, NewConverter(), "keys", home)
+	require.NoError(t, err)
+	require.Contains(t, errOut, "WARNING: invalid conversion specs file")
+
+	_, _, err = runPreRun(t, NewConverter(), "tx", home)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid conversion specs file")
+}
+
+func TestHandlePreRunTokenCache(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(home, "config"), 0755))
+
+	// a corrupt token cache is taken as empty
+	writeTestFile(t, filepath.Join(home, "config"), tokenCacheFile, `{"tokens":`)
+	for _, cmdName := range []string{"keys", "tx"} {
+		it := NewConverter()
+		_, errOut, err := runPreRun(t, it, cmdName, home)
+		require.NoError(t, err, cmdName)
+		require.Contains(t, errOut, "WARNING: invalid token cache", cmdName)
+		require.NotNil(t, it.cache, cmdName)
+		require.Empty(t, it.cache.Tokens, cmdName)
+	}
+
+	cache, err := loadTokenCache(home)
+	require.Error(t, err)
+	btc := tokentypes.NewToken("btc", "Bitcoin", "satoshi", 8, 21000000, 0, false, nil)
+	cache.set(newCachedToken(&btc, time.Now().UTC().Add(-2*defaultTokenCacheTTL)))
+	require.NoError(t, cache.save())
+
+	tests := []struct {
+		name     string
+		args     []string
+		offline  bool
+		expected string
+		errMsg   string
+	}{
+		{"generate only", []string{"--" + flags.FlagGenerateOnly}, true, "", ""},
+		{"tx offline", []string{"--" + flags.FlagOffline}, true, "", ""},
+		{"convert offline", []string{"--" + flagConvertOffline}, true, "", ""},
+		{"stale cached token offline", []string{"--" + flagConvertOffline, "a", "b", "1.5btc"}, true, "150000000satoshi", ""},
+		{"native token offline", []string{"--" + flagConvertOffline, "a", "b", "1.5iris"}, true, "1500000uiris", ""},
+		{"min unit offline", []string{"--" + flagConvertOffline, "a", "b", "1500000uiris"}, true, "1500000uiris", ""},
+		{"missing token offline", []string{"--" + flagConvertOffline, "a", "b", "1.5eth"}, true, "", fmt.Sprintf("token eth not in the token cache, run `%s tokens sync`", version.AppName)},
+		{"missing fee token offline", []string{"--" + flags.FlagOffline, "--fees", "0.3eth", "a", "b", "1.5btc"}, true, "", "token eth not in the token cache"},
+	}
+
+	for _, tc := range tests {
+		it := NewConverter()
+		args, _, err := runPreRun(t, it, "tx", home, tc.args...)
+		if tc.errMsg != "" {
+			require.Error(t, err, tc.name)
+			require.Contains(t, err.Error(), tc.errMsg, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.offline, it.offline, tc.name)
+		if tc.expected != "" {
+			require.Equal(t, tc.expected, args[2], tc.name)
+		}
+	}
+}
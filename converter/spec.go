@@ -0,0 +1,140 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Spec declares the coins of a command to be converted between the main and the min units
+type Spec struct {
+	// Module is the parent command of the command, e.g. "bank"
+	Module string `json:"module,omitempty"`
+	// Command is the name of the command, e.g. "send". The spec applies to all commands
+	// if both Module and Command are empty, which is only allowed for flags.
+	Command string `json:"command,omitempty"`
+	// Args are the indexes of the args holding the coins to be converted to the min units
+	Args []int `json:"args,omitempty"`
+	// Flags are the names of the flags holding the coins to be converted to the min units
+	Flags []string `json:"flags,omitempty"`
+	// Paths are the paths of the coins in the query output to be converted to the main
+	// units, using "*" to match all the elements of a list, e.g. "rewards.*.reward"
+	Paths []string `json:"paths,omitempty"`
+}
+
+// IsGlobal returns true if the spec applies to all commands
+func (s Spec) IsGlobal() bool {
+	return s.Module == "" && s.Command == ""
+}
+
+// Validate returns an error if the spec is invalid
+func (s Spec) Validate() error {
+	if s.IsGlobal() {
+		if len(s.Args) > 0 || len(s.Paths) > 0 {
+			return fmt.Errorf("conversion spec without command can only have flags")
+		}
+		if len(s.Flags) == 0 {
+			return fmt.Errorf("conversion spec without command must have flags")
+		}
+		return nil
+	}
+
+	if s.Module == "" || s.Command == "" {
+		return fmt.Errorf("conversion spec of %s/%s must have both module and command", s.Module, s.Command)
+	}
+	if len(s.Args) == 0 && len(s.Flags) == 0 && len(s.Paths) == 0 {
+		return fmt.Errorf("conversion spec of %s/%s is empty", s.Module, s.Command)
+	}
+	for _, idx := range s.Args {
+		if idx < 0 {
+			return fmt.Errorf("conversion spec of %s/%s has negative arg index %d", s.Module, s.Command, idx)
+		}
+	}
+	for _, flag := range s.Flags {
+		if flag == "" {
+			return fmt.Errorf("conversion spec of %s/%s has empty flag", s.Module, s.Command)
+		}
+	}
+	for _, path := range s.Paths {
+		if err := validatePath(path); err != nil {
+			return fmt.Errorf("conversion spec of %s/%s: %s", s.Module, s.Command, err)
+		}
+	}
+	return nil
+}
+
+// validatePath checks that the path has no empty segment and no wildcard at its root
+func validatePath(path string) error {
+	segments := strings.Split(path, ".")
+	for i, seg := range segments {
+		switch {
+		case seg == "":
+			return fmt.Errorf("invalid path %q", path)
+		case seg == "*" && i == 0:
+			return fmt.Errorf("invalid path %q, the root can not be a wildcard", path)
+		case seg != "*" && strings.Contains(seg, "*"):
+			return fmt.Errorf("invalid path %q, a wildcard must be a whole segment", path)
+		}
+	}
+	return nil
+}
+
+var (
+	mtx   sync.Mutex
+	specs = map[string][]Spec{}
+	order []string
+)
+
+// Register registers the conversion specs of the module, panicking if any spec is invalid.
+// It is meant to be called by the client packages of the modules on init.
+func Register(module string, moduleSpecs ...Spec) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	for _, spec := range moduleSpecs {
+		if err := spec.Validate(); err != nil {
+			panic(err)
+		}
+	}
+	if _, ok := specs[module]; !ok {
+		order = append(order, module)
+	}
+	specs[module] = append(specs[module], moduleSpecs...)
+}
+
+// Specs returns the registered conversion specs in the order of registration
+func Specs() []Spec {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	var all []Spec
+	for _, module := range order {
+		all = append(all, specs[module]...)
+	}
+	return all
+}
+
+// LoadFile returns the conversion specs in the JSON file, or nothing if the file does not exist
+func LoadFile(file string) ([]Spec, error) {
+	bz, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fileSpecs []Spec
+	if err := json.Unmarshal(bz, &fileSpecs); err != nil {
+		return nil, fmt.Errorf("invalid conversion specs file %s: %s", file, err)
+	}
+	for _, spec := range fileSpecs {
+		if err := spec.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid conversion specs file %s: %s", file, err)
+		}
+	}
+	return fileSpecs, nil
+}
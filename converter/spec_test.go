@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    Spec
+		expPass bool
+	}{
+		{"global flags", Spec{Flags: []string{"fees"}}, true},
+		{"global args", Spec{Args: []int{0}}, false},
+		{"global without flags", Spec{}, false},
+		{"missing module", Spec{Command: "send", Args: []int{2}}, false},
+		{"empty spec", Spec{Module: "bank", Command: "send"}, false},
+		{"negative arg", Spec{Module: "bank", Command: "send", Args: []int{-1}}, false},
+		{"args", Spec{Module: "bank", Command: "send", Args: []int{2}}, true},
+		{"wildcard path", Spec{Module: "farm", Command: "farmer", Paths: []string{"list.*.locked"}}, true},
+		{"root wildcard path", Spec{Module: "farm", Command: "farmer", Paths: []string{"*.locked"}}, false},
+		{"partial wildcard path", Spec{Module: "farm", Command: "farmer", Paths: []string{"list.a*.locked"}}, false},
+		{"empty path segment", Spec{Module: "farm", Command: "farmer", Paths: []string{"list..locked"}}, false},
+	}
+
+	for _, tc := range testCases {
+		err := tc.spec.Validate()
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "converter.json")
+
+	specs, err := LoadFile(file)
+	require.NoError(t, err)
+	require.Empty(t, specs)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`[{"module":"farm","command":"stake","args":[1]},{"flags":["reward"]}]`), 0644))
+	specs, err = LoadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []Spec{{Module: "farm", Command: "stake", Args: []int{1}}, {Flags: []string{"reward"}}}, specs)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`[{"module":"farm"}]`), 0644))
+	_, err = LoadFile(file)
+	require.Error(t, err)
+}
@@ -0,0 +1,8 @@
+/*
+Package sm2 implements the keys on the sm2 curve of the Chinese national standard
+GB/T 32918, signing with the sm3 digest and the default user id.
+
+The keys are registered on the legacy amino codec used by the keyring, so that
+the sm2 keys recovered from the legacy keystores can be imported and used to sign.
+*/
+package sm2
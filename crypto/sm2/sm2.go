@@ -0,0 +1,225 @@
+package sm2
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/tjfoc/gmsm/sm2"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ cryptotypes.PrivKey = &PrivKey{}
+var _ codec.AminoMarshaler = &PrivKey{}
+
+const (
+	PrivKeySize   = 32
+	PubKeySize    = 33
+	SignatureSize = 64
+	keyType       = "sm2"
+	PrivKeyName   = "tendermint/PrivKeySm2"
+	PubKeyName    = "tendermint/PubKeySm2"
+)
+
+// Bytes returns the byte representation of the Private Key.
+func (privKey *PrivKey) Bytes() []byte {
+	return privKey.Key
+}
+
+// PubKey returns the compressed public key of the private key
+func (privKey *PrivKey) PubKey() cryptotypes.PubKey {
+	return &PubKey{Key: sm2.Compress(&privKey.sm2PrivKey().PublicKey)}
+}
+
+// Equals - you probably don't need to use this.
+// Runs in constant time based on length of the keys.
+func (privKey *PrivKey) Equals(other cryptotypes.LedgerPrivKey) bool {
+	return privKey.Type() == other.Type() && subtle.ConstantTimeCompare(privKey.Bytes(), other.Bytes()) == 1
+}
+
+func (privKey *PrivKey) Type() string {
+	return keyType
+}
+
+// Sign signs the msg with the default user id, returning the signature as r || s
+func (privKey *PrivKey) Sign(msg []byte) ([]byte, error) {
+	r, s, err := sm2.Sm2Sign(privKey.sm2PrivKey(), msg, nil, crypto.CReader())
+	if err != nil {
+		return nil, err
+	}
+
+	sig := make([]byte, SignatureSize)
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	copy(sig[SignatureSize/2-len(rBytes):SignatureSize/2], rBytes)
+	copy(sig[SignatureSize-len(sBytes):], sBytes)
+	return sig, nil
+}
+
+// MarshalAmino overrides Amino binary marshalling.
+func (privKey PrivKey) MarshalAmino() ([]byte, error) {
+	return privKey.Key, nil
+}
+
+// UnmarshalAmino overrides Amino binary marshalling.
+func (privKey *PrivKey) UnmarshalAmino(bz []byte) error {
+	if len(bz) != PrivKeySize {
+		return fmt.Errorf("invalid privkey size")
+	}
+	privKey.Key = bz
+
+	return nil
+}
+
+// MarshalAminoJSON overrides Amino JSON marshalling.
+func (privKey PrivKey) MarshalAminoJSON() ([]byte, error) {
+	return privKey.MarshalAmino()
+}
+
+// UnmarshalAminoJSON overrides Amino JSON marshalling.
+func (privKey *PrivKey) UnmarshalAminoJSON(bz []byte) error {
+	return privKey.UnmarshalAmino(bz)
+}
+
+func (privKey *PrivKey) sm2PrivKey() *sm2.PrivateKey {
+	curve := sm2.P256Sm2()
+	d := new(big.Int).SetBytes(privKey.Key)
+	x, y := curve.ScalarBaseMult(privKey.Key)
+	return &sm2.PrivateKey{
+		PublicKey: sm2.PublicKey{Curve: curve, X: x, Y: y},
+		D:         d,
+	}
+}
+
+// GenPrivKey generates a new sm2 private key.
+// It uses OS randomness to generate the private key.
+func GenPrivKey() *PrivKey {
+	return &PrivKey{Key: genPrivKey(crypto.CReader())}
+}
+
+// genPrivKey generates a new sm2 private key using the provided reader.
+func genPrivKey(rand io.Reader) []byte {
+	n := sm2.P256Sm2().Params().N
+	privKeyBytes := make([]byte, PrivKeySize)
+	d := new(big.Int)
+	for {
+		if _, err := io.ReadFull(rand, privKeyBytes); err != nil {
+			panic(err)
+		}
+
+		d.SetBytes(privKeyBytes)
+		// break if we found a valid point (i.e. > 0 and < N - 1, as d + 1 is inverted when signing)
+		if d.Sign() > 0 && d.Cmp(new(big.Int).Sub(n, big.NewInt(1))) < 0 {
+			break
+		}
+	}
+
+	return privKeyBytes
+}
+
+//-------------------------------------
+
+var _ cryptotypes.PubKey = &PubKey{}
+var _ codec.AminoMarshaler = &PubKey{}
+
+// Address returns the address of the public key: SHA256(pubkey)[:20]
+func (pubKey *PubKey) Address() crypto.Address {
+	if len(pubKey.Key) != PubKeySize {
+		panic("length of pubkey is incorrect")
+	}
+
+	return crypto.Address(tmhash.SumTruncated(pubKey.Key))
+}
+
+// Bytes returns the pubkey byte format.
+func (pubKey *PubKey) Bytes() []byte {
+	return pubKey.Key
+}
+
+// VerifySignature verifies the r || s signature of the msg with the default user id
+func (pubKey *PubKey) VerifySignature(msg []byte, sig []byte) bool {
+	if len(sig) != SignatureSize {
+		return false
+	}
+
+	pub, ok := decompress(pubKey.Key)
+	if !ok {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(sig[:SignatureSize/2])
+	s := new(big.Int).SetBytes(sig[SignatureSize/2:])
+	return sm2.Sm2Verify(pub, msg, nil, r, s)
+}
+
+func (pubKey *PubKey) Type() string {
+	return keyType
+}
+
+func (pubKey *PubKey) Equals(other cryptotypes.PubKey) bool {
+	return pubKey.Type() == other.Type() && bytes.Equal(pubKey.Bytes(), other.Bytes())
+}
+
+// MarshalAmino overrides Amino binary marshalling.
+func (pubKey PubKey) MarshalAmino() ([]byte, error) {
+	return pubKey.Key, nil
+}
+
+// UnmarshalAmino overrides Amino binary marshalling.
+func (pubKey *PubKey) UnmarshalAmino(bz []byte) error {
+	if len(bz) != PubKeySize {
+		return errors.Wrap(errors.ErrInvalidPubKey, "invalid pubkey size")
+	}
+	pubKey.Key = bz
+
+	return nil
+}
+
+// MarshalAminoJSON overrides Amino JSON marshalling.
+func (pubKey PubKey) MarshalAminoJSON() ([]byte, error) {
+	return pubKey.MarshalAmino()
+}
+
+// UnmarshalAminoJSON overrides Amino JSON marshalling.
+func (pubKey *PubKey) UnmarshalAminoJSON(bz []byte) error {
+	return pubKey.UnmarshalAmino(bz)
+}
+
+// decompress returns the point of the compressed public key, which is the parity
+// of the y-coordinate (0 or 1) followed with the x-coordinate; unlike sm2.Decompress
+// it rejects the keys which are not on the curve instead of panicking
+func decompress(bz []byte) (*sm2.PublicKey, bool) {
+	if len(bz) != PubKeySize || bz[0] > 1 {
+		return nil, false
+	}
+
+	curve := sm2.P256Sm2()
+	params := curve.Params()
+	x := new(big.Int).SetBytes(bz[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, false
+	}
+
+	// y² = x³ - 3x + b
+	y2 := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	y2.Sub(y2, new(big.Int).Mul(x, big.NewInt(3)))
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil, false
+	}
+	if y.Bit(0) != uint(bz[0]) {
+		y.Sub(params.P, y)
+	}
+
+	return &sm2.PublicKey{Curve: curve, X: x, Y: y}, true
+}
@@ -0,0 +1,81 @@
+package sm2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	privKey := GenPrivKey()
+	pubKey := privKey.PubKey()
+	require.Len(t, pubKey.Bytes(), PubKeySize)
+	require.Len(t, pubKey.Address(), 20)
+
+	msg := []byte("hello world")
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.Len(t, sig, SignatureSize)
+	require.True(t, pubKey.VerifySignature(msg, sig))
+
+	require.False(t, pubKey.VerifySignature([]byte("hello"), sig))
+	require.False(t, pubKey.VerifySignature(msg, sig[1:]))
+	require.False(t, GenPrivKey().PubKey().VerifySignature(msg, sig))
+
+	// the keys whose x-coordinate is not on the curve are rejected
+	invalid := make([]byte, PubKeySize)
+	invalid[PubKeySize-1] = 1
+	require.False(t, (&PubKey{Key: invalid}).VerifySignature(msg, sig))
+	invalid[0] = 2
+	require.False(t, (&PubKey{Key: invalid}).VerifySignature(msg, sig))
+}
+
+func TestAminoCodec(t *testing.T) {
+	privKey := GenPrivKey()
+
+	bz, err := legacy.Cdc.MarshalBinaryBare(privKey)
+	require.NoError(t, err)
+	decoded, err := legacy.PrivKeyFromBytes(bz)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(decoded))
+
+	bz, err = legacy.Cdc.MarshalBinaryBare(privKey.PubKey())
+	require.NoError(t, err)
+	pubKey, err := legacy.PubKeyFromBytes(bz)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().Equals(pubKey))
+}
+
+func TestKeyringSign(t *testing.T) {
+	const passphrase = "12345678"
+	privKey := GenPrivKey()
+
+	// the armored sm2 key is imported into the keyring, which signs with it
+	kr := keyring.NewInMemory()
+	require.NoError(t, kr.ImportPrivKey("alice", crypto.EncryptArmorPrivKey(privKey, passphrase, keyType), passphrase))
+
+	info, err := kr.Key("alice")
+	require.NoError(t, err)
+	require.Equal(t, hd.PubKeyType(keyType), info.GetAlgo())
+	require.Equal(t, sdk.AccAddress(privKey.PubKey().Address()), info.GetAddress())
+
+	msg := []byte("hello world")
+	sig, pubKey, err := kr.Sign("alice", msg)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().Equals(pubKey))
+	require.True(t, pubKey.VerifySignature(msg, sig))
+
+	// and exports it again as an sm2 armor
+	armor, err := kr.ExportPrivKeyArmor("alice", passphrase)
+	require.NoError(t, err)
+	exported, algo, err := crypto.UnarmorDecryptPrivKey(armor, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, keyType, algo)
+	require.True(t, privKey.Equals(exported))
+}
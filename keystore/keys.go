@@ -1,50 +1,155 @@
 package keystore
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 
-	tmcrypto "github.com/tendermint/tendermint/crypto"
-	"github.com/tendermint/tendermint/crypto/secp256k1"
-
 	"github.com/cosmos/cosmos-sdk/crypto"
+	sdked25519 "github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/crypto/sm2"
+)
+
+// keystoreVersion is the version of the keystore files written by iris
+const keystoreVersion = "1"
+
+// supported key types of the keystore files
+const (
+	KeyTypeSecp256k1 = "secp256k1"
+	KeyTypeEd25519   = "ed25519"
+	KeyTypeSM2       = "sm2"
 )
 
 // RecoveryAndExportPrivKeyArmor return the new private key armor from a old keystoreFile
 func RecoveryAndExportPrivKeyArmor(keystore []byte, password string) (armor string, err error) {
-	priv, err := recoveryFromKeyStore(keystore, password)
+	return RecoveryAndExportPrivKeyArmorWithType(keystore, password, "")
+}
+
+// RecoveryAndExportPrivKeyArmorWithType return the new private key armor from a old keystoreFile,
+// using the given key type hint if the keystoreFile does not carry its key type
+func RecoveryAndExportPrivKeyArmorWithType(keystore []byte, password, keyType string) (armor string, err error) {
+	priv, err := recoveryFromKeyStore(keystore, password, keyType)
 	if err != nil {
 		return "", err
 	}
 	return exportPrivKeyArmor(priv, password)
 }
 
-func recoveryFromKeyStore(keystore []byte, auth string) (tmcrypto.PrivKey, error) {
-	if auth == "" {
-		return nil, fmt.Errorf("Password is missing ")
+// DecryptKeyStore returns the private key of the keystoreFile, using the given key type
+// hint if the keystoreFile does not carry its key type
+func DecryptKeyStore(keystore []byte, password, keyType string) (cryptotypes.PrivKey, error) {
+	return recoveryFromKeyStore(keystore, password, keyType)
+}
+
+// DecryptKeyStoreBytes verifies the MAC of the keystoreFile and returns its decrypted key bytes
+// with the key type, which is the one of the keystoreFile or else the given key type hint;
+// ErrDecrypt is returned if the password does not match the MAC
+func DecryptKeyStoreBytes(keystore []byte, password, keyType string) ([]byte, string, error) {
+	if password == "" {
+		return nil, "", fmt.Errorf("Password is missing ")
 	}
 
 	var encryptedKey EncryptedKeyJSON
 	if err := json.Unmarshal(keystore, &encryptedKey); err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	switch {
+	case encryptedKey.KeyType == "":
+	case keyType == "":
+		keyType = encryptedKey.KeyType
+	case keyType != encryptedKey.KeyType:
+		return nil, "", fmt.Errorf("key type %s conflicts with the key type %s of the keystore", keyType, encryptedKey.KeyType)
 	}
 
-	keyBytes, err := decryptKey(&encryptedKey, auth)
+	keyBytes, err := decryptKey(&encryptedKey, password)
+	if err != nil {
+		return nil, "", err
+	}
+	return keyBytes, keyType, nil
+}
+
+func recoveryFromKeyStore(keystore []byte, auth, keyType string) (cryptotypes.PrivKey, error) {
+	keyBytes, keyType, err := DecryptKeyStoreBytes(keystore, auth, keyType)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(keyBytes) != 32 {
-		return nil, fmt.Errorf("Len of Keybytes is not equal to 32 ")
+	return PrivKeyFromBytes(keyBytes, keyType)
+}
+
+// PrivKeyFromBytes returns the private key of the given type, which defaults to secp256k1
+func PrivKeyFromBytes(keyBytes []byte, keyType string) (cryptotypes.PrivKey, error) {
+	switch keyType {
+	case "", KeyTypeSecp256k1:
+		if len(keyBytes) != 32 {
+			return nil, fmt.Errorf("Len of Keybytes is not equal to 32 ")
+		}
+		return &sdksecp256k1.PrivKey{Key: keyBytes}, nil
+
+	case KeyTypeEd25519:
+		// the key may be stored either as the 32 bytes seed or as the 64 bytes private key
+		switch len(keyBytes) {
+		case ed25519.SeedSize:
+			return &sdked25519.PrivKey{Key: ed25519.NewKeyFromSeed(keyBytes)}, nil
+		case ed25519.PrivateKeySize:
+			return &sdked25519.PrivKey{Key: keyBytes}, nil
+		default:
+			return nil, fmt.Errorf("Len of ed25519 Keybytes is neither %d nor %d ", ed25519.SeedSize, ed25519.PrivateKeySize)
+		}
+
+	case KeyTypeSM2:
+		if len(keyBytes) != sm2.PrivKeySize {
+			return nil, fmt.Errorf("Len of sm2 Keybytes is not equal to %d ", sm2.PrivKeySize)
+		}
+		return &sm2.PrivKey{Key: keyBytes}, nil
+
+	default:
+		return nil, fmt.Errorf("Unsupported key type: %s", keyType)
 	}
+}
 
-	return secp256k1.PrivKey(keyBytes), nil
+func exportPrivKeyArmor(privKey cryptotypes.PrivKey, password string) (armor string, err error) {
+	return crypto.EncryptArmorPrivKey(privKey, password, privKey.Type()), nil
 }
 
-func exportPrivKeyArmor(privKey tmcrypto.PrivKey, password string) (armor string, err error) {
-	priv := sdksecp256k1.PrivKey{
-		Key: privKey.Bytes(),
+// ExportKeyStore returns the keystore file of the private key encrypted with the password,
+// using the given KDF which is either pbkdf2 or scrypt
+func ExportKeyStore(privKey cryptotypes.PrivKey, password, kdf string) ([]byte, error) {
+	if password == "" {
+		return nil, fmt.Errorf("Password is missing ")
+	}
+
+	var keyType string
+	switch privKey.(type) {
+	case *sdksecp256k1.PrivKey:
+		keyType = KeyTypeSecp256k1
+	case *sdked25519.PrivKey:
+		keyType = KeyTypeEd25519
+	case *sm2.PrivKey:
+		keyType = KeyTypeSM2
+	default:
+		return nil, fmt.Errorf("Unsupported key type: %s", privKey.Type())
 	}
-	return crypto.EncryptArmorPrivKey(&priv, password, "secp256k1"), nil
+
+	cryptoJSON, err := encryptKey(privKey.Bytes(), password, kdf)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(EncryptedKeyJSON{
+		Address: sdk.AccAddress(privKey.PubKey().Address()).String(),
+		Crypto:  cryptoJSON,
+		ID:      id,
+		Version: keystoreVersion,
+		KeyType: keyType,
+	})
 }
@@ -1,9 +1,20 @@
 package keystore
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	sdked25519 "github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/crypto/sm2"
 )
 
 func TestRecoveryAndExportPrivKeyArmor(t *testing.T) {
@@ -11,3 +22,193 @@ func TestRecoveryAndExportPrivKeyArmor(t *testing.T) {
 	_, err := RecoveryAndExportPrivKeyArmor([]byte(keystore), "1234567890")
 	require.NoError(t, err)
 }
+
+// test vectors of the Web3 Secret Storage Definition, re-encrypted with the sha256 MAC used by the iris keystores
+func TestDecryptKey(t *testing.T) {
+	const (
+		password = "testpassword"
+		privKey  = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	)
+
+	tests := []struct {
+		name     string
+		keystore string
+	}{
+		{
+			KDFPBKDF2,
+			`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"213b18eaf5af22f3e238ab9f1d55ec2ba73f7e8fb3e1b0da7128c0f97bc93b93","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"6ef03fdece88a5a9c29dac5a2730ee68d77ff2e9a007e04188e93e44183944ba"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":"3"}`,
+		},
+		{
+			KDFScrypt,
+			`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"b160ff7e6d855b53a3f8d65e4b2850584cfaa01751807f19d07c298de16f802d","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":8,"p":1,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"cfcd53bb7a0581a5275d894a2e22ec706c3ef28880658d8d869a45b952f912e9"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":"3"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		var encryptedKey EncryptedKeyJSON
+		require.NoError(t, json.Unmarshal([]byte(tc.keystore), &encryptedKey), tc.name)
+
+		keyBytes, err := decryptKey(&encryptedKey, password)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, privKey, hex.EncodeToString(keyBytes), tc.name)
+
+		_, err = decryptKey(&encryptedKey, "wrongpassword")
+		require.Equal(t, ErrDecrypt, err, tc.name)
+	}
+}
+
+// test vectors of the Web3 Secret Storage Definition as exported by geth
+// (accounts/keystore/testdata/v3_test_vector.json), with their keccak256 MAC
+// and numeric version
+func TestDecryptEthereumKey(t *testing.T) {
+	const (
+		password = "testpassword"
+		privKey  = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	)
+
+	tests := []struct {
+		name     string
+		keystore string
+	}{
+		{
+			KDFPBKDF2,
+			`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		},
+		{
+			KDFScrypt,
+			`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		},
+	}
+
+	for _, tc := range tests {
+		var encryptedKey EncryptedKeyJSON
+		require.NoError(t, json.Unmarshal([]byte(tc.keystore), &encryptedKey), tc.name)
+		require.Equal(t, Version("3"), encryptedKey.Version, tc.name)
+
+		keyBytes, err := decryptKey(&encryptedKey, password)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, privKey, hex.EncodeToString(keyBytes), tc.name)
+
+		_, err = decryptKey(&encryptedKey, "wrongpassword")
+		require.Equal(t, ErrDecrypt, err, tc.name)
+	}
+}
+
+func TestGetKDFKeyInvalidParams(t *testing.T) {
+	const salt = "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+
+	tests := []struct {
+		name   string
+		kdf    string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"missing kdf", "", map[string]interface{}{"salt": salt, "dklen": 32}, "KDF is missing"},
+		{"unsupported kdf", "argon2", map[string]interface{}{"salt": salt, "dklen": 32}, "Unsupported KDF: argon2"},
+		{"missing salt", KDFScrypt, map[string]interface{}{"dklen": 32, "n": 2, "r": 1, "p": 1}, "salt is missing"},
+		{"invalid salt", KDFScrypt, map[string]interface{}{"salt": "xyz", "dklen": 32, "n": 2, "r": 1, "p": 1}, "invalid KDF param salt"},
+		{"short dklen", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 16, "n": 2, "r": 1, "p": 1}, "must be at least 32"},
+		{"fractional n", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 32, "n": 2.5, "r": 1, "p": 1}, "n: 2.5, must be an integer"},
+		{"missing p", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 32, "n": 2, "r": 1}, "p is missing"},
+		{"n not power of 2", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 32, "n": 3, "r": 1, "p": 1}, "must be a power of 2"},
+		{"n too large", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 32, "n": 1 << 21, "r": 1, "p": 1}, "must be a power of 2 not greater than 1048576"},
+		{"r too large", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 32, "n": 1 << 20, "r": 1 << 20, "p": 1}, "r: 1048576, must not be greater than 32"},
+		{"p too large", KDFScrypt, map[string]interface{}{"salt": salt, "dklen": 32, "n": 2, "r": 1, "p": 1 << 20}, "p: 1048576, must not be greater than 16"},
+		{"c too large", KDFPBKDF2, map[string]interface{}{"salt": salt, "dklen": 32, "c": 10000001, "prf": "hmac-sha256"}, "c: 10000001, must not be greater than 10000000"},
+		{"negative c", KDFPBKDF2, map[string]interface{}{"salt": salt, "dklen": 32, "c": -1, "prf": "hmac-sha256"}, "must be positive"},
+		{"unsupported prf", KDFPBKDF2, map[string]interface{}{"salt": salt, "dklen": 32, "c": 1, "prf": "hmac-sha512"}, "Unsupported PBKDF2 PRF"},
+	}
+
+	for _, tc := range tests {
+		_, err := getKDFKey(CryptoJSON{KDF: tc.kdf, KDFParams: tc.params}, "password")
+		require.Error(t, err, tc.name)
+		require.Contains(t, err.Error(), tc.errMsg, tc.name)
+	}
+
+	// numeric params encoded as strings are accepted
+	_, err := getKDFKey(CryptoJSON{
+		KDF:       KDFScrypt,
+		KDFParams: map[string]interface{}{"salt": salt, "dklen": "32", "n": "2", "r": "1", "p": "1"},
+	}, "password")
+	require.NoError(t, err)
+}
+
+func TestExportKeyStore(t *testing.T) {
+	for _, privKey := range []cryptotypes.PrivKey{sdksecp256k1.GenPrivKey(), sdked25519.GenPrivKey(), sm2.GenPrivKey()} {
+		for _, kdf := range []string{KDFPBKDF2, KDFScrypt} {
+			bz, err := ExportKeyStore(privKey, "1234567890", kdf)
+			require.NoError(t, err, kdf)
+
+			var encryptedKey EncryptedKeyJSON
+			require.NoError(t, json.Unmarshal(bz, &encryptedKey), kdf)
+			require.Equal(t, kdf, encryptedKey.Crypto.KDF)
+			require.Equal(t, privKey.Type(), encryptedKey.KeyType)
+			require.Equal(t, sdk.AccAddress(privKey.PubKey().Address()).String(), encryptedKey.Address)
+
+			recovered, err := recoveryFromKeyStore(bz, "1234567890", "")
+			require.NoError(t, err, kdf)
+			require.True(t, privKey.Equals(recovered), kdf)
+
+			armor, err := RecoveryAndExportPrivKeyArmor(bz, "1234567890")
+			require.NoError(t, err)
+			_, algo, err := crypto.UnarmorDecryptPrivKey(armor, "1234567890")
+			require.NoError(t, err)
+			require.Equal(t, privKey.Type(), algo)
+		}
+	}
+
+	privKey := sdksecp256k1.GenPrivKey()
+	_, err := ExportKeyStore(privKey, "1234567890", "argon2")
+	require.Error(t, err)
+	_, err = ExportKeyStore(privKey, "", KDFPBKDF2)
+	require.Error(t, err)
+}
+
+func TestRecoveryWithKeyType(t *testing.T) {
+	privKey := sdked25519.GenPrivKey()
+	seed := privKey.Key[:ed25519.SeedSize]
+
+	cryptoJSON, err := encryptKey(seed, "1234567890", KDFPBKDF2)
+	require.NoError(t, err)
+	// a legacy keystore without the key type
+	bz, err := json.Marshal(EncryptedKeyJSON{Crypto: cryptoJSON, Version: "1"})
+	require.NoError(t, err)
+
+	// the ed25519 seed is not taken as a secp256k1 key by mistake
+	recovered, err := recoveryFromKeyStore(bz, "1234567890", "")
+	require.NoError(t, err)
+	require.Equal(t, KeyTypeSecp256k1, recovered.Type())
+	require.False(t, privKey.Equals(recovered))
+
+	recovered, err = recoveryFromKeyStore(bz, "1234567890", KeyTypeEd25519)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(recovered))
+
+	_, err = recoveryFromKeyStore(bz, "1234567890", "sr25519")
+	require.Error(t, err)
+
+	// the key type hint must agree with the key type of the keystore
+	bz, err = json.Marshal(EncryptedKeyJSON{Crypto: cryptoJSON, Version: "1", KeyType: KeyTypeEd25519})
+	require.NoError(t, err)
+	_, err = recoveryFromKeyStore(bz, "1234567890", KeyTypeEd25519)
+	require.NoError(t, err)
+	_, err = recoveryFromKeyStore(bz, "1234567890", KeyTypeSecp256k1)
+	require.Error(t, err)
+
+	// a legacy sm2 keystore is recovered with the key type hint, and armored as sm2
+	sm2PrivKey := sm2.GenPrivKey()
+	cryptoJSON, err = encryptKey(sm2PrivKey.Key, "1234567890", KDFScrypt)
+	require.NoError(t, err)
+	bz, err = json.Marshal(EncryptedKeyJSON{Crypto: cryptoJSON, Version: "1"})
+	require.NoError(t, err)
+
+	recovered, err = recoveryFromKeyStore(bz, "1234567890", KeyTypeSM2)
+	require.NoError(t, err)
+	require.True(t, sm2PrivKey.Equals(recovered))
+
+	armor, err := RecoveryAndExportPrivKeyArmorWithType(bz, "1234567890", KeyTypeSM2)
+	require.NoError(t, err)
+	_, algo, err := crypto.UnarmorDecryptPrivKey(armor, "1234567890")
+	require.NoError(t, err)
+	require.Equal(t, KeyTypeSM2, algo)
+}
@@ -4,16 +4,24 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"strconv"
 
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
 )
 
 var (
-	errDecrypt = errors.New("could not decrypt key with given passphrase")
+	// ErrDecrypt is returned when the MAC of the keystore does not match the passphrase
+	ErrDecrypt = errors.New("could not decrypt key with given passphrase")
 )
 
 // PlainKeyJSON define a struct TODO
@@ -29,7 +37,27 @@ type EncryptedKeyJSON struct {
 	Address string     `json:"address"`
 	Crypto  CryptoJSON `json:"crypto"`
 	ID      string     `json:"id"`
-	Version string     `json:"version"`
+	Version Version    `json:"version"`
+	KeyType string     `json:"key_type,omitempty"`
+}
+
+// Version is the version of a keystore file, which is a string in the iris keystores
+// and a number in the Ethereum ones
+type Version string
+
+// UnmarshalJSON accepts the version either as a JSON string or a JSON number
+func (v *Version) UnmarshalJSON(bz []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(bz, &n); err == nil {
+		*v = Version(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return fmt.Errorf("invalid keystore version: %s", bz)
+	}
+	*v = Version(s)
+	return nil
 }
 
 // CryptoJSON define a struct TODO
@@ -47,6 +75,10 @@ type cipherparamsJSON struct {
 }
 
 func decryptKey(keyProtected *EncryptedKeyJSON, auth string) ([]byte, error) {
+	if keyProtected.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("Unsupported cipher: %s", keyProtected.Crypto.Cipher)
+	}
+
 	mac, err := hex.DecodeString(keyProtected.Crypto.MAC)
 	if err != nil {
 		return nil, err
@@ -70,9 +102,14 @@ func decryptKey(keyProtected *EncryptedKeyJSON, auth string) ([]byte, error) {
 	bufferValue := make([]byte, len(cipherText)+16)
 	copy(bufferValue[0:16], derivedKey[16:32])
 	copy(bufferValue[16:], cipherText[:])
-	calculatedMAC := sha256.Sum256((bufferValue))
+	// the iris keystores use a sha256 MAC while the Ethereum ones use a keccak256 MAC
+	calculatedMAC := sha256.Sum256(bufferValue)
 	if !bytes.Equal(calculatedMAC[:], mac) {
-		return nil, errDecrypt
+		keccak := sha3.NewLegacyKeccak256()
+		keccak.Write(bufferValue)
+		if !bytes.Equal(keccak.Sum(nil), mac) {
+			return nil, ErrDecrypt
+		}
 	}
 
 	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
@@ -82,36 +119,210 @@ func decryptKey(keyProtected *EncryptedKeyJSON, auth string) ([]byte, error) {
 	return plainText, err
 }
 
+// supported key derivation functions
+const (
+	KDFPBKDF2 = "pbkdf2"
+	KDFScrypt = "scrypt"
+)
+
+// standard KDF params of the encrypted keys
+const (
+	pbkdf2C     = 262144
+	scryptN     = 1 << 18
+	scryptR     = 8
+	scryptP     = 1
+	scryptMaxN  = 1 << 20
+	scryptMaxR  = 32
+	scryptMaxP  = 16
+	pbkdf2MaxC  = 10000000
+	kdfKeyLen   = 32
+	kdfSaltLen  = 32
+	aesBlockLen = aes.BlockSize
+)
+
+func encryptKey(keyBytes []byte, auth, kdf string) (CryptoJSON, error) {
+	salt := make([]byte, kdfSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return CryptoJSON{}, err
+	}
+
+	var kdfParams map[string]interface{}
+	switch kdf {
+	case KDFPBKDF2:
+		kdfParams = map[string]interface{}{
+			"c":     pbkdf2C,
+			"dklen": kdfKeyLen,
+			"prf":   "hmac-sha256",
+			"salt":  hex.EncodeToString(salt),
+		}
+	case KDFScrypt:
+		kdfParams = map[string]interface{}{
+			"n":     scryptN,
+			"r":     scryptR,
+			"p":     scryptP,
+			"dklen": kdfKeyLen,
+			"salt":  hex.EncodeToString(salt),
+		}
+	default:
+		return CryptoJSON{}, fmt.Errorf("Unsupported KDF: %s", kdf)
+	}
+
+	cryptoJSON := CryptoJSON{
+		Cipher:    "aes-128-ctr",
+		KDF:       kdf,
+		KDFParams: kdfParams,
+	}
+	derivedKey, err := getKDFKey(cryptoJSON, auth)
+	if err != nil {
+		return CryptoJSON{}, err
+	}
+
+	iv := make([]byte, aesBlockLen)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return CryptoJSON{}, err
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], keyBytes, iv)
+	if err != nil {
+		return CryptoJSON{}, err
+	}
+	mac := sha256.Sum256(append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+
+	cryptoJSON.CipherText = hex.EncodeToString(cipherText)
+	cryptoJSON.CipherParams = cipherparamsJSON{IV: hex.EncodeToString(iv)}
+	cryptoJSON.MAC = hex.EncodeToString(mac[:])
+	return cryptoJSON, nil
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	u := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, u); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
 func getKDFKey(cryptoJSON CryptoJSON, auth string) ([]byte, error) {
 	authArray := []byte(auth)
-	if cryptoJSON.KDFParams["salt"] == nil || cryptoJSON.KDFParams["dklen"] == nil ||
-		cryptoJSON.KDFParams["c"] == nil || cryptoJSON.KDFParams["prf"] == nil {
-		return nil, errors.New("invalid KDF params, must contains c, dklen, prf and salt")
+	params := cryptoJSON.KDFParams
+
+	salt, err := kdfParamString(params, "salt")
+	if err != nil {
+		return nil, err
 	}
-	salt, err := hex.DecodeString(cryptoJSON.KDFParams["salt"].(string))
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KDF param salt: %s", err)
+	}
+	dkLen, err := kdfParamInt(params, "dklen")
 	if err != nil {
 		return nil, err
 	}
-	dkLen := ensureInt(cryptoJSON.KDFParams["dklen"])
+	// the first 16 bytes are the encryption key and the next 16 bytes are for the MAC
+	if dkLen < 32 {
+		return nil, fmt.Errorf("invalid KDF param dklen: %d, must be at least 32", dkLen)
+	}
 
-	if cryptoJSON.KDF == "pbkdf2" {
-		c := ensureInt(cryptoJSON.KDFParams["c"])
-		prf := cryptoJSON.KDFParams["prf"].(string)
+	switch cryptoJSON.KDF {
+	case KDFPBKDF2:
+		c, err := kdfParamInt(params, "c")
+		if err != nil {
+			return nil, err
+		}
+		if c > pbkdf2MaxC {
+			return nil, fmt.Errorf("invalid KDF param c: %d, must not be greater than %d", c, pbkdf2MaxC)
+		}
+		prf, err := kdfParamString(params, "prf")
+		if err != nil {
+			return nil, err
+		}
 		if prf != "hmac-sha256" {
 			return nil, fmt.Errorf("Unsupported PBKDF2 PRF: %s", prf)
 		}
-		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
+		return pbkdf2.Key(authArray, saltBytes, c, dkLen, sha256.New), nil
+
+	case KDFScrypt:
+		n, err := kdfParamInt(params, "n")
+		if err != nil {
+			return nil, err
+		}
+		if n > scryptMaxN || n&(n-1) != 0 {
+			return nil, fmt.Errorf("invalid KDF param n: %d, must be a power of 2 not greater than %d", n, scryptMaxN)
+		}
+		r, err := kdfParamInt(params, "r")
+		if err != nil {
+			return nil, err
+		}
+		if r > scryptMaxR {
+			return nil, fmt.Errorf("invalid KDF param r: %d, must not be greater than %d", r, scryptMaxR)
+		}
+		p, err := kdfParamInt(params, "p")
+		if err != nil {
+			return nil, err
+		}
+		if p > scryptMaxP {
+			return nil, fmt.Errorf("invalid KDF param p: %d, must not be greater than %d", p, scryptMaxP)
+		}
+		key, err := scrypt.Key(authArray, saltBytes, n, r, p, dkLen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt params: %s", err)
+		}
 		return key, nil
+
+	case "":
+		return nil, errors.New("KDF is missing")
+
+	default:
+		return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
 	}
-	return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
 }
 
-func ensureInt(x interface{}) int {
-	res, ok := x.(int)
+// kdfParamString returns the string KDF param of the given name
+func kdfParamString(params map[string]interface{}, name string) (string, error) {
+	v, ok := params[name]
+	if !ok || v == nil {
+		return "", fmt.Errorf("invalid KDF params, %s is missing", name)
+	}
+	res, ok := v.(string)
 	if !ok {
-		res = int(x.(float64))
+		return "", fmt.Errorf("invalid KDF param %s: %v, must be a string", name, v)
+	}
+	return res, nil
+}
+
+// kdfParamInt returns the positive integer KDF param of the given name,
+// which may be encoded as a JSON number or a decimal string
+func kdfParamInt(params map[string]interface{}, name string) (int, error) {
+	v, ok := params[name]
+	if !ok || v == nil {
+		return 0, fmt.Errorf("invalid KDF params, %s is missing", name)
+	}
+
+	var res int
+	switch x := v.(type) {
+	case int:
+		res = x
+	case float64:
+		if x != math.Trunc(x) || x > math.MaxInt32 {
+			return 0, fmt.Errorf("invalid KDF param %s: %v, must be an integer", name, v)
+		}
+		res = int(x)
+	case string:
+		i, err := strconv.ParseInt(x, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid KDF param %s: %v, must be an integer", name, v)
+		}
+		res = int(i)
+	default:
+		return 0, fmt.Errorf("invalid KDF param %s: %v, must be an integer", name, v)
+	}
+
+	if res <= 0 {
+		return 0, fmt.Errorf("invalid KDF param %s: %d, must be positive", name, res)
 	}
-	return res
+	return res, nil
 }
 
 func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
@@ -0,0 +1,169 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// AppState is the app state of a genesis file, keyed by the module names
+type AppState map[string]json.RawMessage
+
+// GenesisTransformer transforms the genesis sections of a module into the format of a version.
+// A transformer must leave the sections already in that format unchanged.
+type GenesisTransformer struct {
+	Module    string
+	Name      string
+	Transform func(appState AppState) error
+}
+
+// GenesisMigration is the transformers bringing a genesis into the format of a version, which run in order
+type GenesisMigration struct {
+	Version      string
+	Transformers []GenesisTransformer
+}
+
+// GenesisReport is a transformer run on a genesis
+type GenesisReport struct {
+	Version string `json:"version"`
+	Module  string `json:"module"`
+	Name    string `json:"name"`
+}
+
+// Validate returns an error if the migration is invalid
+func (m GenesisMigration) Validate() error {
+	if m.Version == "" {
+		return fmt.Errorf("genesis migration version cannot be empty")
+	}
+	for i, t := range m.Transformers {
+		if t.Module == "" || t.Name == "" {
+			return fmt.Errorf("transformer %d of genesis migration %s must have a module and a name", i, m.Version)
+		}
+		if t.Transform == nil {
+			return fmt.Errorf("transformer %s/%s of genesis migration %s has no transform", t.Module, t.Name, m.Version)
+		}
+	}
+	return nil
+}
+
+// GenesisRegistry holds the genesis migrations in the order of the versions
+type GenesisRegistry struct {
+	migrations []GenesisMigration
+}
+
+// NewGenesisRegistry returns an empty GenesisRegistry
+func NewGenesisRegistry() *GenesisRegistry {
+	return &GenesisRegistry{}
+}
+
+// Register registers the migration after the ones registered so far, panicking if the migration
+// is invalid or its version already registered
+func (r *GenesisRegistry) Register(migration GenesisMigration) *GenesisRegistry {
+	if err := migration.Validate(); err != nil {
+		panic(err)
+	}
+	if r.index(migration.Version) >= 0 {
+		panic(fmt.Sprintf("genesis migration %s already registered", migration.Version))
+	}
+	r.migrations = append(r.migrations, migration)
+	return r
+}
+
+// Versions returns the registered versions in order
+func (r *GenesisRegistry) Versions() []string {
+	versions := make([]string, len(r.migrations))
+	for i, m := range r.migrations {
+		versions[i] = m.Version
+	}
+	return versions
+}
+
+// Migrate runs the transformers of all the versions up to the target one in order on the app state
+func (r *GenesisRegistry) Migrate(appState AppState, target string) ([]GenesisReport, error) {
+	last := r.index(target)
+	if last < 0 {
+		return nil, fmt.Errorf("unknown genesis migration version %s, expected one of %v", target, r.Versions())
+	}
+
+	reports := []GenesisReport{}
+	for _, m := range r.migrations[:last+1] {
+		for _, t := range m.Transformers {
+			if err := t.Transform(appState); err != nil {
+				return reports, fmt.Errorf("transformer %s/%s of genesis migration %s failed: %w", t.Module, t.Name, m.Version, err)
+			}
+			reports = append(reports, GenesisReport{Version: m.Version, Module: t.Module, Name: t.Name})
+		}
+	}
+	return reports, nil
+}
+
+func (r *GenesisRegistry) index(version string) int {
+	for i, m := range r.migrations {
+		if m.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// GenesisBalance returns the balance of the address in the bank genesis state
+func GenesisBalance(cdc codec.JSONMarshaler, appState AppState, address string) (sdk.Coins, error) {
+	var bankGenesis banktypes.GenesisState
+	if err := cdc.UnmarshalJSON(appState[banktypes.ModuleName], &bankGenesis); err != nil {
+		return nil, err
+	}
+	for _, balance := range bankGenesis.Balances {
+		if balance.Address == address {
+			return balance.Coins, nil
+		}
+	}
+	return sdk.NewCoins(), nil
+}
+
+// SendGenesisCoins moves the amount between two balances of the bank genesis state
+func SendGenesisCoins(cdc codec.JSONMarshaler, appState AppState, from, to string, amount sdk.Coins) error {
+	if amount.IsZero() {
+		return nil
+	}
+
+	var bankGenesis banktypes.GenesisState
+	if err := cdc.UnmarshalJSON(appState[banktypes.ModuleName], &bankGenesis); err != nil {
+		return err
+	}
+
+	fromIdx, toIdx := -1, -1
+	for i, balance := range bankGenesis.Balances {
+		switch balance.Address {
+		case from:
+			fromIdx = i
+		case to:
+			toIdx = i
+		}
+	}
+	if fromIdx < 0 {
+		return fmt.Errorf("%s has no balance", from)
+	}
+
+	coins, negative := bankGenesis.Balances[fromIdx].Coins.SafeSub(amount)
+	if negative {
+		return fmt.Errorf("insufficient balance of %s: %s < %s", from, bankGenesis.Balances[fromIdx].Coins, amount)
+	}
+	bankGenesis.Balances[fromIdx].Coins = coins
+
+	if toIdx < 0 {
+		bankGenesis.Balances = append(bankGenesis.Balances, banktypes.Balance{Address: to})
+		toIdx = len(bankGenesis.Balances) - 1
+	}
+	bankGenesis.Balances[toIdx].Coins = bankGenesis.Balances[toIdx].Coins.Add(amount...)
+	bankGenesis.Balances = banktypes.SanitizeGenesisBalances(bankGenesis.Balances)
+
+	bz, err := cdc.MarshalJSON(&bankGenesis)
+	if err != nil {
+		return err
+	}
+	appState[banktypes.ModuleName] = bz
+	return nil
+}
@@ -0,0 +1,73 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestGenesisRegistryMigrate(t *testing.T) {
+	var order []string
+	transformer := func(name string, err error) GenesisTransformer {
+		return GenesisTransformer{Module: "test", Name: name, Transform: func(appState AppState) error {
+			order = append(order, name)
+			return err
+		}}
+	}
+
+	r := NewGenesisRegistry().
+		Register(GenesisMigration{Version: "v1.1", Transformers: []GenesisTransformer{transformer("a", nil), transformer("b", nil)}}).
+		Register(GenesisMigration{Version: "v1.2", Transformers: []GenesisTransformer{transformer("c", nil)}}).
+		Register(GenesisMigration{Version: "v1.3", Transformers: []GenesisTransformer{transformer("d", errors.New("failed"))}})
+	require.Equal(t, []string{"v1.1", "v1.2", "v1.3"}, r.Versions())
+
+	reports, err := r.Migrate(AppState{}, "v1.2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, order)
+	require.Equal(t, GenesisReport{Version: "v1.2", Module: "test", Name: "c"}, reports[2])
+
+	order = nil
+	reports, err = r.Migrate(AppState{}, "v1.3")
+	require.Error(t, err)
+	require.Len(t, reports, 3)
+	require.Equal(t, []string{"a", "b", "c", "d"}, order)
+
+	_, err = r.Migrate(AppState{}, "v2.0")
+	require.Error(t, err)
+
+	require.Panics(t, func() { r.Register(GenesisMigration{Version: "v1.1"}) })
+	require.Panics(t, func() { r.Register(GenesisMigration{}) })
+	require.Panics(t, func() {
+		r.Register(GenesisMigration{Version: "v1.4", Transformers: []GenesisTransformer{{Module: "test", Name: "e"}}})
+	})
+}
+
+func TestSendGenesisCoins(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	from := sdk.AccAddress("from________________").String()
+	to := sdk.AccAddress("to__________________").String()
+
+	bankGenesis := banktypes.DefaultGenesisState()
+	bankGenesis.Balances = []banktypes.Balance{{Address: from, Coins: sdk.NewCoins(sdk.NewInt64Coin("uiris", 10))}}
+	bz, err := cdc.MarshalJSON(bankGenesis)
+	require.NoError(t, err)
+	appState := AppState{banktypes.ModuleName: bz}
+
+	require.NoError(t, SendGenesisCoins(cdc, appState, from, to, sdk.NewCoins(sdk.NewInt64Coin("uiris", 4))))
+	balance, err := GenesisBalance(cdc, appState, from)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uiris", 6)), balance)
+	balance, err = GenesisBalance(cdc, appState, to)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uiris", 4)), balance)
+
+	require.Error(t, SendGenesisCoins(cdc, appState, from, to, sdk.NewCoins(sdk.NewInt64Coin("uiris", 7))))
+	require.Error(t, SendGenesisCoins(cdc, appState, sdk.AccAddress("none________________").String(), to, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1))))
+	require.NoError(t, SendGenesisCoins(cdc, appState, from, to, sdk.NewCoins()))
+}
@@ -0,0 +1,96 @@
+package htlc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+
+	"github.com/irisnet/irishub/migrate"
+)
+
+// legacyGenesisState is the htlc genesis state before v1.1, keyed by the hash locks
+type legacyGenesisState struct {
+	PendingHTLCs map[string]json.RawMessage `json:"pending_htlcs"`
+}
+
+// MigrateGenesis transforms the htlc genesis state exported before v1.1 into the current format with the
+// given params. The open htlcs are re-keyed by their ids, the expired ones are refunded to their senders
+// in the bank genesis state and the closed ones are dropped. The current format is left unchanged.
+func MigrateGenesis(cdc codec.JSONMarshaler, appState migrate.AppState, params htlctypes.Params) error {
+	var legacy legacyGenesisState
+	if err := json.Unmarshal(appState[htlctypes.ModuleName], &legacy); err != nil {
+		return err
+	}
+	if legacy.PendingHTLCs == nil {
+		return nil
+	}
+
+	moduleAddr := authtypes.NewModuleAddress(htlctypes.ModuleName).String()
+	hashLocks := make([]string, 0, len(legacy.PendingHTLCs))
+	for hashLock := range legacy.PendingHTLCs {
+		hashLocks = append(hashLocks, hashLock)
+	}
+	sort.Strings(hashLocks)
+
+	htlcs := []htlctypes.HTLC{}
+	for _, hashLockStr := range hashLocks {
+		var htlc OldHTLC
+		if err := cdc.UnmarshalJSON(legacy.PendingHTLCs[hashLockStr], &htlc); err != nil {
+			return fmt.Errorf("invalid htlc %s: %s", hashLockStr, err)
+		}
+
+		hashLock, err := hex.DecodeString(hashLockStr)
+		if err != nil {
+			return fmt.Errorf("invalid hash lock %s: %s", hashLockStr, err)
+		}
+		sender, err := sdk.AccAddressFromBech32(htlc.Sender)
+		if err != nil {
+			return err
+		}
+		receiver, err := sdk.AccAddressFromBech32(htlc.To)
+		if err != nil {
+			return err
+		}
+
+		switch htlc.State {
+		case Open:
+			htlcs = append(htlcs, htlctypes.HTLC{
+				Id:                   htlctypes.GetID(sender, receiver, htlc.Amount, hashLock).String(),
+				Sender:               htlc.Sender,
+				To:                   htlc.To,
+				ReceiverOnOtherChain: htlc.ReceiverOnOtherChain,
+				Amount:               htlc.Amount,
+				HashLock:             tmbytes.HexBytes(hashLock).String(),
+				Secret:               htlc.Secret,
+				Timestamp:            htlc.Timestamp,
+				ExpirationHeight:     htlc.ExpirationHeight,
+				State:                htlctypes.Open,
+				Direction:            htlctypes.None,
+			})
+		case Expired:
+			// Refund expired htlc
+			if err := migrate.SendGenesisCoins(cdc, appState, moduleAddr, htlc.Sender, htlc.Amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	genesis := htlctypes.DefaultGenesisState()
+	genesis.Params = params
+	genesis.Htlcs = htlcs
+	bz, err := cdc.MarshalJSON(genesis)
+	if err != nil {
+		return err
+	}
+	appState[htlctypes.ModuleName] = bz
+	return nil
+}
@@ -0,0 +1,68 @@
+package htlc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+
+	"github.com/irisnet/irishub/migrate"
+)
+
+func TestMigrateGenesis(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	sender := sdk.AccAddress("sender______________").String()
+	moduleAddr := authtypes.NewModuleAddress(htlctypes.ModuleName).String()
+
+	bankGenesis := banktypes.DefaultGenesisState()
+	bankGenesis.Balances = []banktypes.Balance{{Address: moduleAddr, Coins: sdk.NewCoins(sdk.NewInt64Coin("uiris", 30))}}
+	bankBz, err := cdc.MarshalJSON(bankGenesis)
+	require.NoError(t, err)
+
+	legacyHTLC := func(amount int64, state HTLCStatus) json.RawMessage {
+		bz, err := cdc.MarshalJSON(&OldHTLC{
+			Sender:           sender,
+			To:               sender,
+			Amount:           sdk.NewCoins(sdk.NewInt64Coin("uiris", amount)),
+			Timestamp:        1,
+			ExpirationHeight: 100,
+			State:            state,
+		})
+		require.NoError(t, err)
+		return bz
+	}
+	htlcBz, err := json.Marshal(legacyGenesisState{PendingHTLCs: map[string]json.RawMessage{
+		strings.Repeat("aa", 32): legacyHTLC(10, Open),
+		strings.Repeat("bb", 32): legacyHTLC(20, Expired),
+		strings.Repeat("cc", 32): legacyHTLC(5, Completed),
+	}})
+	require.NoError(t, err)
+
+	appState := migrate.AppState{banktypes.ModuleName: bankBz, htlctypes.ModuleName: htlcBz}
+	require.NoError(t, MigrateGenesis(cdc, appState, PresetHTLTParams()))
+
+	var genesis htlctypes.GenesisState
+	require.NoError(t, cdc.UnmarshalJSON(appState[htlctypes.ModuleName], &genesis))
+	require.NoError(t, htlctypes.ValidateGenesis(genesis))
+	require.Len(t, genesis.Htlcs, 1)
+	require.Equal(t, strings.Repeat("AA", 32), genesis.Htlcs[0].HashLock)
+	require.Equal(t, PresetHTLTParams(), genesis.Params)
+
+	balance, err := migrate.GenesisBalance(cdc, appState, sender)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uiris", 20)), balance)
+
+	// the current format is left unchanged
+	migrated := appState[htlctypes.ModuleName]
+	require.NoError(t, MigrateGenesis(cdc, appState, PresetHTLTParams()))
+	require.Equal(t, migrated, appState[htlctypes.ModuleName])
+}
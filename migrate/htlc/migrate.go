@@ -1,12 +1,15 @@
 package htlc
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
 
 	htlckeeper "github.com/irisnet/irismod/modules/htlc/keeper"
@@ -14,8 +17,21 @@ import (
 )
 
 func Migrate(ctx sdk.Context, cdc codec.Marshaler, k htlckeeper.Keeper, bk bankkeeper.Keeper, key *sdk.KVStoreKey) error {
+	_, err := MigrateWithReport(ctx, cdc, k, bk, key, PresetHTLTParams())
+	return err
+}
+
+// MigrateWithReport migrates the htlcs and installs the given params, returning the report of the migration
+func MigrateWithReport(
+	ctx sdk.Context, cdc codec.Marshaler, k htlckeeper.Keeper, bk bankkeeper.Keeper, key *sdk.KVStoreKey, params htlctypes.Params,
+) (*Report, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := newReport()
 	if err := k.EnsureModuleAccountPermissions(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
 	store := ctx.KVStore(key)
@@ -34,11 +50,11 @@ func Migrate(ctx sdk.Context, cdc codec.Marshaler, k htlckeeper.Keeper, bk bankk
 
 		sender, err := sdk.AccAddressFromBech32(htlc.Sender)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		receiver, err := sdk.AccAddressFromBech32(htlc.To)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		id := htlctypes.GetID(sender, receiver, htlc.Amount, hashLock)
 		expirationHeight := htlc.ExpirationHeight
@@ -56,9 +72,10 @@ func Migrate(ctx sdk.Context, cdc codec.Marshaler, k htlckeeper.Keeper, bk bankk
 			// Refund expired htlc
 			state = htlctypes.Refunded
 			if err := bk.SendCoinsFromModuleToAccount(ctx, htlctypes.ModuleName, sender, htlc.Amount); err != nil {
-				return err
+				return nil, err
 			}
 			closedBlock = uint64(ctx.BlockHeight())
+			report.addRefund(htlc.Sender, htlc.Amount)
 		case Refunded:
 			state = htlctypes.Refunded
 		}
@@ -84,14 +101,30 @@ func Migrate(ctx sdk.Context, cdc codec.Marshaler, k htlckeeper.Keeper, bk bankk
 		}
 		// Set new htlc
 		k.SetHTLC(ctx, newHTLC, id)
+		report.HTLCs = append(report.HTLCs, HTLCReport{
+			HashLock: hashLock.String(),
+			ID:       id.String(),
+			Sender:   htlc.Sender,
+			OldState: htlc.State.String(),
+			NewState: state.String(),
+		})
 	}
 
 	// Set default params
-	k.SetParams(ctx, PresetHTLTParams())
-
-	return nil
+	k.SetParams(ctx, params)
+	report.Params = params
+
+	ctx.Logger().Info(
+		"migrated htlcs",
+		"htlcs", len(report.HTLCs),
+		"refunds", len(report.Refunds),
+		"asset_params", len(params.AssetParams),
+	)
+	return report, nil
 }
 
+// PresetHTLTParams returns the htlc params installed by the migration, whose deputy
+// addresses are encoded with the configured bech32 prefix
 func PresetHTLTParams() htlctypes.Params {
 	return htlctypes.Params{
 		AssetParams: []htlctypes.AssetParam{
@@ -104,7 +137,7 @@ func PresetHTLTParams() htlctypes.Params {
 					TimePeriod:     time.Duration(0),
 				},
 				Active:        true,
-				DeputyAddress: "iaa1junhkdhuamtdz3ah6d5mfp6w9sxmlwera7mruz",
+				DeputyAddress: deputyAddress("iaa1junhkdhuamtdz3ah6d5mfp6w9sxmlwera7mruz"),
 				FixedFee:      sdk.NewInt(1000),
 				MinSwapAmount: sdk.NewInt(1001),
 				MaxSwapAmount: sdk.NewInt(1000000000000),
@@ -120,7 +153,7 @@ func PresetHTLTParams() htlctypes.Params {
 					TimePeriod:     time.Duration(0),
 				},
 				Active:        true,
-				DeputyAddress: "iaa1z2sdef0ypat9lq7wsxrt7ue3uzdnzcsd34wsl4",
+				DeputyAddress: deputyAddress("iaa1z2sdef0ypat9lq7wsxrt7ue3uzdnzcsd34wsl4"),
 				FixedFee:      sdk.NewInt(20000),
 				MinSwapAmount: sdk.NewInt(20001),
 				MaxSwapAmount: sdk.NewInt(15000000000000),
@@ -130,3 +163,36 @@ func PresetHTLTParams() htlctypes.Params {
 		},
 	}
 }
+
+// PlanInfo is the part of the upgrade plan info read by the htlc migration
+type PlanInfo struct {
+	HTLCParams *json.RawMessage `json:"htlc_params,omitempty"`
+}
+
+// PlanHTLTParams returns the htlc params in the "htlc_params" field of the upgrade plan info,
+// which is JSON. The info agreed on by the governance is the same on every node, unlike a local
+// file; PresetHTLTParams is returned if the info is not a JSON object or does not carry the params.
+func PlanHTLTParams(cdc codec.JSONMarshaler, info string) (htlctypes.Params, error) {
+	var planInfo PlanInfo
+	if err := json.Unmarshal([]byte(info), &planInfo); err != nil || planInfo.HTLCParams == nil {
+		return PresetHTLTParams(), nil
+	}
+
+	var params htlctypes.Params
+	if err := cdc.UnmarshalJSON(*planInfo.HTLCParams, &params); err != nil {
+		return params, fmt.Errorf("invalid htlc params of the upgrade plan info: %s", err)
+	}
+	if err := params.Validate(); err != nil {
+		return params, fmt.Errorf("invalid htlc params of the upgrade plan info: %s", err)
+	}
+	return params, nil
+}
+
+// deputyAddress re-encodes the deputy address of the mainnet with the configured bech32 prefix
+func deputyAddress(mainnetAddr string) string {
+	_, bz, err := bech32.DecodeAndConvert(mainnetAddr)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.AccAddress(bz).String()
+}
@@ -0,0 +1,96 @@
+package htlc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+)
+
+const (
+	// FlagPresetParamsFile defines the flag of the JSON file of the htlc params installed by the genesis
+	// migration, while the upgrade installs the params of the plan info, see PlanHTLTParams
+	FlagPresetParamsFile = "htlc-preset-params"
+	// FlagReportFile defines the app option of the file the htlc migration report is written into
+	FlagReportFile = "htlc-migration-report"
+)
+
+// HTLCReport records the migration of an htlc
+type HTLCReport struct {
+	HashLock string `json:"hash_lock"`
+	ID       string `json:"id"`
+	Sender   string `json:"sender"`
+	OldState string `json:"old_state"`
+	NewState string `json:"new_state"`
+}
+
+// Refund is the amount refunded to a sender for its expired htlcs
+type Refund struct {
+	Sender string    `json:"sender"`
+	Amount sdk.Coins `json:"amount"`
+}
+
+// Report is the report of the htlc migration
+type Report struct {
+	HTLCs   []HTLCReport     `json:"htlcs"`
+	Refunds []Refund         `json:"refunds"`
+	Params  htlctypes.Params `json:"params"`
+}
+
+func newReport() *Report {
+	return &Report{
+		HTLCs:   []HTLCReport{},
+		Refunds: []Refund{},
+	}
+}
+
+// addRefund adds the amount to the refund of the sender, keeping the refunds sorted by the senders
+func (r *Report) addRefund(sender string, amount sdk.Coins) {
+	i := sort.Search(len(r.Refunds), func(i int) bool { return r.Refunds[i].Sender >= sender })
+	if i < len(r.Refunds) && r.Refunds[i].Sender == sender {
+		r.Refunds[i].Amount = r.Refunds[i].Amount.Add(amount...)
+		return
+	}
+	r.Refunds = append(r.Refunds, Refund{})
+	copy(r.Refunds[i+1:], r.Refunds[i:])
+	r.Refunds[i] = Refund{Sender: sender, Amount: amount}
+}
+
+// WriteFile writes the report as JSON into the file
+func (r Report) WriteFile(cdc codec.JSONMarshaler, file string) error {
+	params, err := cdc.MarshalJSON(&r.Params)
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.MarshalIndent(struct {
+		HTLCs   []HTLCReport    `json:"htlcs"`
+		Refunds []Refund        `json:"refunds"`
+		Params  json.RawMessage `json:"params"`
+	}{r.HTLCs, r.Refunds, params}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, bz, 0644)
+}
+
+// LoadPresetHTLTParams returns the htlc params in the JSON file, which replace PresetHTLTParams
+func LoadPresetHTLTParams(cdc codec.JSONMarshaler, file string) (htlctypes.Params, error) {
+	var params htlctypes.Params
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return params, err
+	}
+	if err := cdc.UnmarshalJSON(bz, &params); err != nil {
+		return params, fmt.Errorf("invalid htlc params file %s: %s", file, err)
+	}
+	if err := params.Validate(); err != nil {
+		return params, fmt.Errorf("invalid htlc params file %s: %s", file, err)
+	}
+	return params, nil
+}
@@ -0,0 +1,87 @@
+package htlc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/address"
+)
+
+func init() {
+	address.ConfigureBech32Prefix()
+}
+
+func TestReportAddRefund(t *testing.T) {
+	report := newReport()
+	report.addRefund("b", sdk.NewCoins(sdk.NewInt64Coin("uiris", 1)))
+	report.addRefund("c", sdk.NewCoins(sdk.NewInt64Coin("uiris", 2)))
+	report.addRefund("a", sdk.NewCoins(sdk.NewInt64Coin("uiris", 3)))
+	report.addRefund("b", sdk.NewCoins(sdk.NewInt64Coin("uiris", 4), sdk.NewInt64Coin("htltbnb", 5)))
+
+	require.Equal(t, []Refund{
+		{Sender: "a", Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", 3))},
+		{Sender: "b", Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", 5), sdk.NewInt64Coin("htltbnb", 5))},
+		{Sender: "c", Amount: sdk.NewCoins(sdk.NewInt64Coin("uiris", 2))},
+	}, report.Refunds)
+}
+
+func TestLoadPresetHTLTParams(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	dir := t.TempDir()
+
+	params := PresetHTLTParams()
+	bz, err := cdc.MarshalJSON(&params)
+	require.NoError(t, err)
+	file := filepath.Join(dir, "params.json")
+	require.NoError(t, ioutil.WriteFile(file, bz, 0644))
+
+	loaded, err := LoadPresetHTLTParams(cdc, file)
+	require.NoError(t, err)
+	require.Equal(t, params, loaded)
+
+	params.AssetParams[0].Denom = ""
+	bz, err = cdc.MarshalJSON(&params)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(file, bz, 0644))
+	_, err = LoadPresetHTLTParams(cdc, file)
+	require.Error(t, err)
+
+	_, err = LoadPresetHTLTParams(cdc, filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
+
+func TestPlanHTLTParams(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+
+	params := PresetHTLTParams()
+	params.AssetParams = params.AssetParams[1:]
+	bz, err := cdc.MarshalJSON(&params)
+	require.NoError(t, err)
+
+	planParams, err := PlanHTLTParams(cdc, fmt.Sprintf(`{"binaries":{},"htlc_params":%s}`, bz))
+	require.NoError(t, err)
+	require.Equal(t, params, planParams)
+
+	// the preset params are installed if the info does not carry the params
+	for _, info := range []string{"", "https://example.com/upgrade.json", `{"binaries":{}}`, `{"htlc_params":null}`} {
+		planParams, err = PlanHTLTParams(cdc, info)
+		require.NoError(t, err, info)
+		require.Equal(t, PresetHTLTParams(), planParams, info)
+	}
+
+	params.AssetParams[0].Denom = ""
+	bz, err = cdc.MarshalJSON(&params)
+	require.NoError(t, err)
+	_, err = PlanHTLTParams(cdc, fmt.Sprintf(`{"htlc_params":%s}`, bz))
+	require.Error(t, err)
+	_, err = PlanHTLTParams(cdc, `{"htlc_params":{"asset_params":"invalid"}}`)
+	require.Error(t, err)
+}
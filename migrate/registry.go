@@ -0,0 +1,137 @@
+package migrate
+
+import (
+	"fmt"
+	"time"
+
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+// Step is a migration step of a module run by an upgrade, which is given the upgrade plan
+// approved on chain so that its parameters, e.g. in the plan info, are the same on every node
+type Step struct {
+	Module  string
+	Name    string
+	Migrate func(ctx sdk.Context, plan upgradetypes.Plan) error
+}
+
+// Plan is an upgrade along with its store upgrades and its migration steps, which run in order
+type Plan struct {
+	Name          string
+	StoreUpgrades storetypes.StoreUpgrades
+	Steps         []Step
+}
+
+// StepReport is the result of a migration step
+type StepReport struct {
+	Module   string        `json:"module"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report is the result of the migration steps of a plan
+type Report struct {
+	Plan  string       `json:"plan"`
+	Steps []StepReport `json:"steps"`
+}
+
+// Validate returns an error if the plan is invalid
+func (p Plan) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("upgrade plan name cannot be empty")
+	}
+	for i, step := range p.Steps {
+		if step.Module == "" || step.Name == "" {
+			return fmt.Errorf("step %d of upgrade plan %s must have a module and a name", i, p.Name)
+		}
+		if step.Migrate == nil {
+			return fmt.Errorf("step %s/%s of upgrade plan %s has no migration", step.Module, step.Name, p.Name)
+		}
+	}
+	return nil
+}
+
+// Run runs the migration steps in order for the upgrade plan, logging the progress of each step.
+// It stops at the first failed step, returning the report of the steps run so far.
+func (p Plan) Run(ctx sdk.Context, plan upgradetypes.Plan) (Report, error) {
+	logger := ctx.Logger().With("module", "migrate", "plan", p.Name)
+	report := Report{Plan: p.Name}
+
+	for i, step := range p.Steps {
+		logger.Info(
+			"running migration step",
+			"step", fmt.Sprintf("%d/%d", i+1, len(p.Steps)),
+			"migration", step.Module,
+			"name", step.Name,
+		)
+
+		start := time.Now()
+		err := step.Migrate(ctx, plan)
+		stepReport := StepReport{
+			Module:   step.Module,
+			Name:     step.Name,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			stepReport.Error = err.Error()
+			report.Steps = append(report.Steps, stepReport)
+			logger.Error("migration step failed", "migration", step.Module, "name", step.Name, "err", err)
+			return report, fmt.Errorf("step %s/%s of upgrade plan %s failed: %w", step.Module, step.Name, p.Name, err)
+		}
+
+		report.Steps = append(report.Steps, stepReport)
+		logger.Info("migration step completed", "migration", step.Module, "name", step.Name, "duration", stepReport.Duration)
+	}
+
+	logger.Info("upgrade plan completed", "steps", len(p.Steps))
+	return report, nil
+}
+
+// Handler returns the upgrade handler running the migration steps, which panics if any step fails
+func (p Plan) Handler() upgradetypes.UpgradeHandler {
+	return func(ctx sdk.Context, plan upgradetypes.Plan) {
+		if _, err := p.Run(ctx, plan); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Registry holds the known upgrade plans in the order of registration
+type Registry struct {
+	plans []Plan
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers the plan, panicking if the plan is invalid or already registered
+func (r *Registry) Register(plan Plan) *Registry {
+	if err := plan.Validate(); err != nil {
+		panic(err)
+	}
+	if _, ok := r.Plan(plan.Name); ok {
+		panic(fmt.Sprintf("upgrade plan %s already registered", plan.Name))
+	}
+	r.plans = append(r.plans, plan)
+	return r
+}
+
+// Plan returns the plan of the given name
+func (r *Registry) Plan(name string) (Plan, bool) {
+	for _, plan := range r.plans {
+		if plan.Name == name {
+			return plan, true
+		}
+	}
+	return Plan{}, false
+}
+
+// Plans returns all the registered plans
+func (r *Registry) Plans() []Plan {
+	return append([]Plan{}, r.plans...)
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+func TestRegistry(t *testing.T) {
+	noop := func(ctx sdk.Context, plan upgradetypes.Plan) error { return nil }
+	r := NewRegistry().
+		Register(Plan{Name: "v1.1", Steps: []Step{{Module: "htlc", Name: "a", Migrate: noop}}}).
+		Register(Plan{Name: "v1.2"})
+
+	require.Len(t, r.Plans(), 2)
+	plan, ok := r.Plan("v1.2")
+	require.True(t, ok)
+	require.Equal(t, "v1.2", plan.Name)
+	_, ok = r.Plan("v1.3")
+	require.False(t, ok)
+
+	require.Panics(t, func() { r.Register(Plan{Name: "v1.1"}) })
+	require.Panics(t, func() { r.Register(Plan{}) })
+	require.Panics(t, func() { r.Register(Plan{Name: "v1.3", Steps: []Step{{Module: "htlc", Name: "a"}}}) })
+}
+
+func TestPlanRun(t *testing.T) {
+	ctx := sdk.Context{}.WithLogger(log.NewNopLogger())
+
+	var order, infos []string
+	step := func(name string, err error) Step {
+		return Step{Module: "test", Name: name, Migrate: func(ctx sdk.Context, plan upgradetypes.Plan) error {
+			order = append(order, name)
+			infos = append(infos, plan.Info)
+			return err
+		}}
+	}
+
+	// the steps are given the upgrade plan
+	plan := Plan{Name: "v1.1", Steps: []Step{step("a", nil), step("b", nil)}}
+	report, err := plan.Run(ctx, upgradetypes.Plan{Name: "v1.1", Info: "info"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, order)
+	require.Equal(t, []string{"info", "info"}, infos)
+	require.Equal(t, "v1.1", report.Plan)
+	require.Len(t, report.Steps, 2)
+
+	order = nil
+	plan = Plan{Name: "v1.1", Steps: []Step{step("a", errors.New("failed")), step("b", nil)}}
+	report, err = plan.Run(ctx, upgradetypes.Plan{Name: "v1.1"})
+	require.Error(t, err)
+	require.Equal(t, []string{"a"}, order)
+	require.Len(t, report.Steps, 1)
+	require.Equal(t, "failed", report.Steps[0].Error)
+
+	require.Panics(t, func() { plan.Handler()(ctx, upgradetypes.Plan{Name: "v1.1"}) })
+}
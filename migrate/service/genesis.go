@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	servicetypes "github.com/irisnet/irismod/modules/service/types"
+
+	"github.com/irisnet/irishub/migrate"
+)
+
+// MigrateGenesis moves the balance of the old service tax account to the service fee collector in the
+// bank genesis state and lifts the restriction of the service fee denom, as Migrate does on the store.
+// The balance of the old service tax account marks the legacy format, without which the genesis is
+// left unchanged, so that a restriction set in the current format is kept.
+func MigrateGenesis(cdc codec.JSONMarshaler, appState migrate.AppState) error {
+	oldAcc := sdk.AccAddress(crypto.AddressHash([]byte(TaxAccName))).String()
+	balance, err := migrate.GenesisBalance(cdc, appState, oldAcc)
+	if err != nil {
+		return err
+	}
+	if balance.IsZero() {
+		return nil
+	}
+
+	var genesis servicetypes.GenesisState
+	if err := cdc.UnmarshalJSON(appState[servicetypes.ModuleName], &genesis); err != nil {
+		return err
+	}
+	genesis.Params.RestrictedServiceFeeDenom = false
+	bz, err := cdc.MarshalJSON(&genesis)
+	if err != nil {
+		return err
+	}
+	appState[servicetypes.ModuleName] = bz
+
+	feeCollector := authtypes.NewModuleAddress(servicetypes.FeeCollectorName).String()
+	return migrate.SendGenesisCoins(cdc, appState, oldAcc, feeCollector, balance)
+}
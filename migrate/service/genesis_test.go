@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	servicetypes "github.com/irisnet/irismod/modules/service/types"
+
+	"github.com/irisnet/irishub/migrate"
+)
+
+func TestMigrateGenesis(t *testing.T) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	oldAcc := sdk.AccAddress(crypto.AddressHash([]byte(TaxAccName))).String()
+	feeCollector := authtypes.NewModuleAddress(servicetypes.FeeCollectorName).String()
+
+	newAppState := func(balances ...banktypes.Balance) migrate.AppState {
+		bankGenesis := banktypes.DefaultGenesisState()
+		bankGenesis.Balances = balances
+		bankBz, err := cdc.MarshalJSON(bankGenesis)
+		require.NoError(t, err)
+
+		serviceGenesis := servicetypes.DefaultGenesisState()
+		serviceGenesis.Params.RestrictedServiceFeeDenom = true
+		serviceBz, err := cdc.MarshalJSON(serviceGenesis)
+		require.NoError(t, err)
+
+		return migrate.AppState{banktypes.ModuleName: bankBz, servicetypes.ModuleName: serviceBz}
+	}
+	restricted := func(appState migrate.AppState) bool {
+		var genesis servicetypes.GenesisState
+		require.NoError(t, cdc.UnmarshalJSON(appState[servicetypes.ModuleName], &genesis))
+		return genesis.Params.RestrictedServiceFeeDenom
+	}
+
+	// the balance of the old tax account is moved to the fee collector and the restriction lifted
+	appState := newAppState(banktypes.Balance{Address: oldAcc, Coins: sdk.NewCoins(sdk.NewInt64Coin("uiris", 30))})
+	require.NoError(t, MigrateGenesis(cdc, appState))
+	require.False(t, restricted(appState))
+
+	balance, err := migrate.GenesisBalance(cdc, appState, oldAcc)
+	require.NoError(t, err)
+	require.True(t, balance.IsZero())
+	balance, err = migrate.GenesisBalance(cdc, appState, feeCollector)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uiris", 30)), balance)
+
+	// the migrated genesis is left unchanged
+	migrated := migrate.AppState{}
+	for module, bz := range appState {
+		migrated[module] = bz
+	}
+	require.NoError(t, MigrateGenesis(cdc, appState))
+	require.Equal(t, migrated, appState)
+
+	// the current format without the old tax account keeps its restriction
+	appState = newAppState(banktypes.Balance{Address: feeCollector, Coins: sdk.NewCoins(sdk.NewInt64Coin("uiris", 10))})
+	current := migrate.AppState{}
+	for module, bz := range appState {
+		current[module] = bz
+	}
+	require.NoError(t, MigrateGenesis(cdc, appState))
+	require.True(t, restricted(appState))
+	require.Equal(t, current, appState)
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// GlobalFeeDecorator checks if the tx fee is at least as large as the global
+// minimum gas prices set by governance. Unlike the local minimum gas prices of
+// a node, the global ones are enforced in both CheckTx and DeliverTx, so that
+// all the validators agree on whether a tx pays enough fees.
+type GlobalFeeDecorator struct {
+	k Keeper
+}
+
+// NewGlobalFeeDecorator returns an instance of GlobalFeeDecorator
+func NewGlobalFeeDecorator(k Keeper) GlobalFeeDecorator {
+	return GlobalFeeDecorator{
+		k: k,
+	}
+}
+
+// AnteHandle checks the tx fee against the global minimum gas prices
+func (gfd GlobalFeeDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	// genesis txs are delivered at height zero without fees
+	if simulate || ctx.BlockHeight() == 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	minGasPrices := gfd.k.MinimumGasPrices(ctx)
+	if minGasPrices.IsZero() {
+		return next(ctx, tx, simulate)
+	}
+
+	// fee = ceil(minGasPrice * gasLimit)
+	requiredFees := make(sdk.Coins, len(minGasPrices))
+	glDec := sdk.NewDec(int64(feeTx.GetGas()))
+	for i, gp := range minGasPrices {
+		fee := gp.Amount.Mul(glDec)
+		requiredFees[i] = sdk.NewCoin(gp.Denom, fee.Ceil().RoundInt())
+	}
+
+	feeCoins := feeTx.GetFee()
+	if !feeCoins.IsAnyGTE(requiredFees) {
+		return ctx, sdkerrors.Wrapf(
+			sdkerrors.ErrInsufficientFee, "insufficient fees for global minimum gas prices; got: %s required: %s", feeCoins, requiredFees,
+		)
+	}
+
+	return next(ctx, tx, simulate)
+}
@@ -0,0 +1,48 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/modules/globalfee/keeper"
+	"github.com/irisnet/irishub/modules/globalfee/types"
+)
+
+func (suite *KeeperTestSuite) TestGlobalFeeDecorator() {
+	suite.keeper.SetParamSet(suite.ctx, types.NewParams(
+		sdk.NewDecCoins(sdk.NewDecCoinFromDec("uiris", sdk.NewDecWithPrec(2, 1))),
+	))
+
+	anteHandler := sdk.ChainAnteDecorators(keeper.NewGlobalFeeDecorator(suite.keeper))
+
+	tests := []struct {
+		name     string
+		fee      sdk.Coins
+		gas      uint64
+		simulate bool
+		height   int64
+		expPass  bool
+	}{
+		{"sufficient fee", sdk.NewCoins(sdk.NewInt64Coin("uiris", 40000)), 200000, false, 1, true},
+		{"insufficient fee", sdk.NewCoins(sdk.NewInt64Coin("uiris", 39999)), 200000, false, 1, false},
+		{"wrong denom", sdk.NewCoins(sdk.NewInt64Coin("stake", 40000)), 200000, false, 1, false},
+		{"simulate", sdk.Coins{}, 200000, true, 1, true},
+		{"genesis", sdk.Coins{}, 200000, false, 0, true},
+	}
+
+	for _, tc := range tests {
+		txBuilder := suite.txConfig.NewTxBuilder()
+		txBuilder.SetFeeAmount(tc.fee)
+		txBuilder.SetGasLimit(tc.gas)
+
+		// the decorator is enforced in both CheckTx and DeliverTx
+		for _, isCheckTx := range []bool{true, false} {
+			ctx := suite.ctx.WithBlockHeight(tc.height).WithIsCheckTx(isCheckTx)
+			_, err := anteHandler(ctx, txBuilder.GetTx(), tc.simulate)
+			if tc.expPass {
+				suite.Require().NoError(err, tc.name)
+			} else {
+				suite.Require().Error(err, tc.name)
+			}
+		}
+	}
+}
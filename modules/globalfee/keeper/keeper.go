@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/irisnet/irishub/modules/globalfee/types"
+)
+
+// Keeper of the globalfee store
+type Keeper struct {
+	paramSpace paramtypes.Subspace
+}
+
+// NewKeeper returns a globalfee keeper
+func NewKeeper(paramSpace paramtypes.Subspace) Keeper {
+	return Keeper{
+		paramSpace: paramSpace.WithKeyTable(types.ParamKeyTable()),
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("%s", types.ModuleName))
+}
+
+// GetParamSet returns globalfee params from the global param store.
+// The params may be absent on a chain upgraded without the globalfee genesis,
+// in which case no global minimum gas prices are enforced.
+func (k Keeper) GetParamSet(ctx sdk.Context) types.Params {
+	params := types.DefaultParams()
+	k.paramSpace.GetIfExists(ctx, types.KeyMinimumGasPrices, &params.MinimumGasPrices)
+	return params
+}
+
+// SetParamSet set globalfee params to the global param store
+func (k Keeper) SetParamSet(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// MinimumGasPrices returns the global minimum gas prices
+func (k Keeper) MinimumGasPrices(ctx sdk.Context) sdk.DecCoins {
+	return k.GetParamSet(ctx).MinimumGasPrices
+}
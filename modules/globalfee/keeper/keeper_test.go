@@ -0,0 +1,78 @@
+package keeper_test
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/irisnet/irishub/modules/globalfee/keeper"
+	"github.com/irisnet/irishub/modules/globalfee/types"
+	"github.com/irisnet/irishub/simapp"
+)
+
+type KeeperTestSuite struct {
+	suite.Suite
+
+	ctx      sdk.Context
+	keeper   keeper.Keeper
+	txConfig client.TxConfig
+}
+
+func (suite *KeeperTestSuite) SetupTest() {
+	encCfg := simapp.MakeTestEncodingConfig()
+
+	key := sdk.NewKVStoreKey(paramstypes.StoreKey)
+	tkey := sdk.NewTransientStoreKey(paramstypes.TStoreKey)
+
+	ms := store.NewCommitMultiStore(dbm.NewMemDB())
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	ms.MountStoreWithDB(tkey, sdk.StoreTypeTransient, nil)
+	suite.Require().NoError(ms.LoadLatestVersion())
+
+	paramsKeeper := paramskeeper.NewKeeper(encCfg.Marshaler, encCfg.Amino, key, tkey)
+
+	suite.ctx = sdk.NewContext(ms, tmproto.Header{Height: 1}, false, log.NewNopLogger())
+	suite.keeper = keeper.NewKeeper(paramsKeeper.Subspace(types.ModuleName))
+	suite.txConfig = encCfg.TxConfig
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+	suite.Run(t, new(KeeperTestSuite))
+}
+
+func (suite *KeeperTestSuite) TestGetParamSetIfNotExist() {
+	suite.Require().Equal(types.DefaultParams(), suite.keeper.GetParamSet(suite.ctx))
+}
+
+func (suite *KeeperTestSuite) TestSetGetParamSet() {
+	params := types.NewParams(sdk.NewDecCoins(sdk.NewDecCoinFromDec("uiris", sdk.NewDecWithPrec(2, 1))))
+	suite.keeper.SetParamSet(suite.ctx, params)
+
+	suite.Require().Equal(params, suite.keeper.GetParamSet(suite.ctx))
+	suite.Require().Equal(params.MinimumGasPrices, suite.keeper.MinimumGasPrices(suite.ctx))
+}
+
+func (suite *KeeperTestSuite) TestGRPCQueryParams() {
+	params := types.NewParams(sdk.NewDecCoins(sdk.NewDecCoinFromDec("uiris", sdk.NewDecWithPrec(2, 1))))
+	suite.keeper.SetParamSet(suite.ctx, params)
+
+	queryHelper := baseapp.NewQueryServerTestHelper(suite.ctx, simapp.MakeTestEncodingConfig().InterfaceRegistry)
+	types.RegisterQueryServer(queryHelper, suite.keeper)
+	queryClient := types.NewQueryClient(queryHelper)
+
+	resp, err := queryClient.Params(gocontext.Background(), &types.QueryParamsRequest{})
+	suite.NoError(err)
+	suite.Equal(params, resp.Params)
+}
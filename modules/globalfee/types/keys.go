@@ -0,0 +1,16 @@
+package types
+
+// nolint
+const (
+	// ModuleName defines the module name
+	ModuleName = "globalfee"
+
+	// RouterKey defines the module's message routing key
+	RouterKey = ModuleName
+
+	// QuerierRoute defines the module's query routing key
+	QuerierRoute = ModuleName
+
+	// Query endpoints supported by the globalfee querier
+	QueryParameters = "parameters"
+)
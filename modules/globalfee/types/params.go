@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+// default paramspace for params keeper
+const (
+	DefaultParamSpace = ModuleName
+)
+
+// Parameter store key
+var (
+	// params store for minimum gas prices
+	KeyMinimumGasPrices = []byte("MinimumGasPrices")
+)
+
+// ParamKeyTable for globalfee module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// NewParams constructs a new Params instance
+func NewParams(minGasPrices sdk.DecCoins) Params {
+	return Params{
+		MinimumGasPrices: minGasPrices,
+	}
+}
+
+// DefaultParams returns default globalfee module parameters
+func DefaultParams() Params {
+	return Params{
+		MinimumGasPrices: sdk.DecCoins{},
+	}
+}
+
+// String implements the Stringer interface.
+func (p Params) String() string {
+	out, _ := yaml.Marshal(p)
+	return string(out)
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyMinimumGasPrices, &p.MinimumGasPrices, validateMinimumGasPrices),
+	}
+}
+
+// Validate returns err if the Params is invalid
+func (p Params) Validate() error {
+	if err := p.MinimumGasPrices.Validate(); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidMinGasPrices, "minimum gas prices [%s] are invalid: %s", p.MinimumGasPrices, err)
+	}
+	return nil
+}
+
+func validateMinimumGasPrices(i interface{}) error {
+	v, ok := i.(sdk.DecCoins)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if err := v.Validate(); err != nil {
+		return fmt.Errorf("minimum gas prices [%s] are invalid: %s", v, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		expectPass bool
+		params     Params
+	}{
+		{true, DefaultParams()},
+		{true, NewParams(sdk.DecCoins{sdk.NewDecCoinFromDec("uiris", sdk.NewDecWithPrec(2, 1))})},
+		{true, NewParams(sdk.DecCoins{sdk.NewDecCoinFromDec("stake", sdk.OneDec()), sdk.NewDecCoinFromDec("uiris", sdk.NewDecWithPrec(2, 1))})},
+		{false, NewParams(sdk.DecCoins{sdk.NewDecCoinFromDec("uiris", sdk.NewDecWithPrec(2, 1)), sdk.NewDecCoinFromDec("stake", sdk.OneDec())})},
+		{false, NewParams(sdk.DecCoins{{Denom: "uiris", Amount: sdk.NewDec(-1)}})},
+	}
+	for i, tc := range tests {
+		err := tc.params.Validate()
+		if tc.expectPass {
+			require.NoError(t, err, "%d: %+v", i, err)
+		} else {
+			require.Error(t, err, "%d: %+v", i, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/irisnet/irishub/modules/memo/types"
+)
+
+// GetQueryCmd returns the cli query commands for the memo module.
+func GetQueryCmd() *cobra.Command {
+	memoQueryCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Querying commands for the memo module",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+	memoQueryCmd.AddCommand(
+		GetCmdQueryMemoRequired(),
+	)
+	return memoQueryCmd
+}
+
+// GetCmdQueryMemoRequired implements a command to query whether an account requires a memo.
+func GetCmdQueryMemoRequired() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "required [address]",
+		Short: "Query whether an account requires a memo on the incoming transfers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.MemoRequired(context.Background(), &types.QueryMemoRequiredRequest{Address: args[0]})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
@@ -0,0 +1,41 @@
+package memo
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/modules/memo/keeper"
+	"github.com/irisnet/irishub/modules/memo/types"
+)
+
+// InitGenesis stores genesis data
+func InitGenesis(ctx sdk.Context, keeper keeper.Keeper, data types.GenesisState) {
+	if err := ValidateGenesis(data); err != nil {
+		panic(fmt.Errorf("failed to initialize memo genesis state: %s", err.Error()))
+	}
+	for _, address := range data.MemoRequiredAddresses {
+		addr, _ := sdk.AccAddressFromBech32(address)
+		keeper.SetMemoRequired(ctx, addr, true)
+	}
+}
+
+// ExportGenesis outputs genesis data
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	var addresses []string
+	k.IterateMemoRequiredAddresses(
+		ctx,
+		func(addr sdk.AccAddress) bool {
+			addresses = append(addresses, addr.String())
+			return false
+		},
+	)
+
+	return types.NewGenesisState(addresses)
+}
+
+// ValidateGenesis performs basic validation of memo genesis data returning an
+// error for any failed validation criteria.
+func ValidateGenesis(data types.GenesisState) error {
+	return types.ValidateGenesis(data)
+}
@@ -0,0 +1,73 @@
+package keeper
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/cosmos-sdk/x/ibc/applications/transfer/types"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+
+	"github.com/irisnet/irishub/modules/memo/types"
+)
+
+// MemoRequiredDecorator rejects the txs without a memo transferring to the accounts
+// which require a memo, e.g. the deposit addresses of the exchanges.
+// It should be called next to ValidateMemoDecorator.
+type MemoRequiredDecorator struct {
+	k Keeper
+}
+
+// NewMemoRequiredDecorator returns an instance of MemoRequiredDecorator
+func NewMemoRequiredDecorator(k Keeper) MemoRequiredDecorator {
+	return MemoRequiredDecorator{
+		k: k,
+	}
+}
+
+// AnteHandle checks the recipients of the bank sends, ibc transfers and htlc creations
+func (mrd MemoRequiredDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	memoTx, ok := tx.(sdk.TxWithMemo)
+	if !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+	}
+	if len(strings.TrimSpace(memoTx.GetMemo())) > 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		for _, recipient := range recipients(msg) {
+			addr, err := sdk.AccAddressFromBech32(recipient)
+			if err != nil {
+				// not an account of this chain, e.g. the receiver of an ibc transfer
+				continue
+			}
+			if mrd.k.IsMemoRequired(ctx, addr) {
+				return ctx, sdkerrors.Wrapf(types.ErrMemoRequired, "%s requires a memo", recipient)
+			}
+		}
+	}
+	return next(ctx, tx, simulate)
+}
+
+// recipients returns the recipients of the transfers in the msg
+func recipients(msg sdk.Msg) []string {
+	switch msg := msg.(type) {
+	case *banktypes.MsgSend:
+		return []string{msg.ToAddress}
+	case *banktypes.MsgMultiSend:
+		addrs := make([]string, len(msg.Outputs))
+		for i, output := range msg.Outputs {
+			addrs[i] = output.Address
+		}
+		return addrs
+	case *ibctransfertypes.MsgTransfer:
+		return []string{msg.Receiver}
+	case *htlctypes.MsgCreateHTLC:
+		return []string{msg.To}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/cosmos-sdk/x/ibc/applications/transfer/types"
+	clienttypes "github.com/cosmos/cosmos-sdk/x/ibc/core/02-client/types"
+
+	htlctypes "github.com/irisnet/irismod/modules/htlc/types"
+
+	"github.com/irisnet/irishub/modules/memo/keeper"
+)
+
+func (suite *KeeperTestSuite) TestMemoRequiredDecorator() {
+	suite.keeper.SetMemoRequired(suite.ctx, addr1, true)
+
+	anteHandler := sdk.ChainAnteDecorators(keeper.NewMemoRequiredDecorator(suite.keeper))
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin("uiris", 1))
+	foreignAddr, err := bech32.ConvertAndEncode("osmo", addr1)
+	suite.Require().NoError(err)
+
+	tests := []struct {
+		name    string
+		msg     sdk.Msg
+		memo    string
+		expPass bool
+	}{
+		{"send with memo", banktypes.NewMsgSend(addr2, addr1, coins), "123456", true},
+		{"send without memo", banktypes.NewMsgSend(addr2, addr1, coins), "", false},
+		{"send with blank memo", banktypes.NewMsgSend(addr2, addr1, coins), "  ", false},
+		{"send to other", banktypes.NewMsgSend(addr1, addr2, coins), "", true},
+		{
+			"multisend without memo",
+			banktypes.NewMsgMultiSend(
+				[]banktypes.Input{banktypes.NewInput(addr2, coins.Add(coins...))},
+				[]banktypes.Output{banktypes.NewOutput(addr2, coins), banktypes.NewOutput(addr1, coins)},
+			),
+			"", false,
+		},
+		{
+			"ibc transfer without memo",
+			ibctransfertypes.NewMsgTransfer("transfer", "channel-0", coins[0], addr2, addr1.String(), clienttypes.NewHeight(0, 100), 0),
+			"", false,
+		},
+		{
+			"ibc transfer to foreign chain",
+			ibctransfertypes.NewMsgTransfer("transfer", "channel-0", coins[0], addr2, foreignAddr, clienttypes.NewHeight(0, 100), 0),
+			"", true,
+		},
+		{"htlc without memo", &htlctypes.MsgCreateHTLC{Sender: addr2.String(), To: addr1.String(), Amount: coins}, "", false},
+		{"htlc with memo", &htlctypes.MsgCreateHTLC{Sender: addr2.String(), To: addr1.String(), Amount: coins}, "123456", true},
+	}
+
+	for _, tc := range tests {
+		txBuilder := suite.txConfig.NewTxBuilder()
+		suite.Require().NoError(txBuilder.SetMsgs(tc.msg))
+		txBuilder.SetMemo(tc.memo)
+
+		_, err := anteHandler(suite.ctx, txBuilder.GetTx(), false)
+		if tc.expPass {
+			suite.Require().NoError(err, tc.name)
+		} else {
+			suite.Require().Error(err, tc.name)
+		}
+	}
+}
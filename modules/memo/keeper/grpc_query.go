@@ -0,0 +1,28 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/modules/memo/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// MemoRequired implements the Query/MemoRequired gRPC method
+func (k Keeper) MemoRequired(c context.Context, req *types.QueryMemoRequiredRequest) (*types.QueryMemoRequiredResponse, error) {
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "empty request")
+	}
+	address, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address: %v", err)
+	}
+	ctx := sdk.UnwrapSDKContext(c)
+
+	return &types.QueryMemoRequiredResponse{Required: k.IsMemoRequired(ctx, address)}, nil
+}
@@ -0,0 +1,65 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/modules/memo/types"
+)
+
+// Keeper of the memo store
+type Keeper struct {
+	cdc      codec.Marshaler
+	storeKey sdk.StoreKey
+}
+
+// NewKeeper returns a memo keeper
+func NewKeeper(cdc codec.Marshaler, key sdk.StoreKey) Keeper {
+	return Keeper{
+		storeKey: key,
+		cdc:      cdc,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("%s", types.ModuleName))
+}
+
+// SetMemoRequired sets whether the account requires a memo on the incoming transfers
+func (k Keeper) SetMemoRequired(ctx sdk.Context, addr sdk.AccAddress, required bool) {
+	store := ctx.KVStore(k.storeKey)
+	if required {
+		store.Set(types.GetMemoRequiredKey(addr), []byte{0x01})
+		return
+	}
+	store.Delete(types.GetMemoRequiredKey(addr))
+}
+
+// IsMemoRequired returns true if the account requires a memo on the incoming transfers
+func (k Keeper) IsMemoRequired(ctx sdk.Context, addr sdk.AccAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(types.GetMemoRequiredKey(addr))
+}
+
+// IterateMemoRequiredAddresses iterates through all the accounts requiring a memo
+func (k Keeper) IterateMemoRequiredAddresses(
+	ctx sdk.Context,
+	op func(addr sdk.AccAddress) (stop bool),
+) {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, types.MemoRequiredKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		addr := sdk.AccAddress(iterator.Key()[len(types.MemoRequiredKey):])
+		if stop := op(addr); stop {
+			break
+		}
+	}
+}
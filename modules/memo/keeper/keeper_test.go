@@ -0,0 +1,102 @@
+package keeper_test
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/modules/memo/keeper"
+	"github.com/irisnet/irishub/modules/memo/types"
+	"github.com/irisnet/irishub/simapp"
+)
+
+var (
+	addr1 = sdk.AccAddress(crypto.AddressHash([]byte("addr1")))
+	addr2 = sdk.AccAddress(crypto.AddressHash([]byte("addr2")))
+)
+
+type KeeperTestSuite struct {
+	suite.Suite
+
+	ctx      sdk.Context
+	keeper   keeper.Keeper
+	txConfig client.TxConfig
+}
+
+func (suite *KeeperTestSuite) SetupTest() {
+	encCfg := simapp.MakeTestEncodingConfig()
+
+	key := sdk.NewKVStoreKey(types.StoreKey)
+
+	ms := store.NewCommitMultiStore(dbm.NewMemDB())
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	suite.Require().NoError(ms.LoadLatestVersion())
+
+	suite.ctx = sdk.NewContext(ms, tmproto.Header{Height: 1}, false, log.NewNopLogger())
+	suite.keeper = keeper.NewKeeper(encCfg.Marshaler, key)
+	suite.txConfig = encCfg.TxConfig
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+	suite.Run(t, new(KeeperTestSuite))
+}
+
+func (suite *KeeperTestSuite) TestSetMemoRequired() {
+	suite.Require().False(suite.keeper.IsMemoRequired(suite.ctx, addr1))
+
+	suite.keeper.SetMemoRequired(suite.ctx, addr1, true)
+	suite.keeper.SetMemoRequired(suite.ctx, addr2, true)
+	suite.Require().True(suite.keeper.IsMemoRequired(suite.ctx, addr1))
+
+	suite.keeper.SetMemoRequired(suite.ctx, addr2, false)
+	suite.Require().False(suite.keeper.IsMemoRequired(suite.ctx, addr2))
+
+	var addrs []sdk.AccAddress
+	suite.keeper.IterateMemoRequiredAddresses(suite.ctx, func(addr sdk.AccAddress) bool {
+		addrs = append(addrs, addr)
+		return false
+	})
+	suite.Require().Equal([]sdk.AccAddress{addr1}, addrs)
+}
+
+func (suite *KeeperTestSuite) TestMsgSetMemoRequired() {
+	msgServer := keeper.NewMsgServerImpl(suite.keeper)
+
+	_, err := msgServer.SetMemoRequired(sdk.WrapSDKContext(suite.ctx), types.NewMsgSetMemoRequired(addr1, true))
+	suite.Require().NoError(err)
+	suite.Require().True(suite.keeper.IsMemoRequired(suite.ctx, addr1))
+
+	_, err = msgServer.SetMemoRequired(sdk.WrapSDKContext(suite.ctx), types.NewMsgSetMemoRequired(addr1, false))
+	suite.Require().NoError(err)
+	suite.Require().False(suite.keeper.IsMemoRequired(suite.ctx, addr1))
+}
+
+func (suite *KeeperTestSuite) TestGRPCQueryMemoRequired() {
+	suite.keeper.SetMemoRequired(suite.ctx, addr1, true)
+
+	queryHelper := baseapp.NewQueryServerTestHelper(suite.ctx, simapp.MakeTestEncodingConfig().InterfaceRegistry)
+	types.RegisterQueryServer(queryHelper, suite.keeper)
+	queryClient := types.NewQueryClient(queryHelper)
+
+	resp, err := queryClient.MemoRequired(gocontext.Background(), &types.QueryMemoRequiredRequest{Address: addr1.String()})
+	suite.Require().NoError(err)
+	suite.Require().True(resp.Required)
+
+	resp, err = queryClient.MemoRequired(gocontext.Background(), &types.QueryMemoRequiredRequest{Address: addr2.String()})
+	suite.Require().NoError(err)
+	suite.Require().False(resp.Required)
+
+	_, err = queryClient.MemoRequired(gocontext.Background(), &types.QueryMemoRequiredRequest{Address: "invalid"})
+	suite.Require().Error(err)
+}
@@ -0,0 +1,10 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// memo module sentinel errors
+var (
+	ErrMemoRequired = sdkerrors.Register(ModuleName, 2, "memo required by the recipient")
+)
@@ -0,0 +1,12 @@
+// nolint
+package types
+
+// memo module event types
+const (
+	EventTypeSetMemoRequired = "set_memo_required"
+
+	AttributeKeyAddress  = "address"
+	AttributeKeyRequired = "required"
+
+	AttributeValueCategory = ModuleName
+)
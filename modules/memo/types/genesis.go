@@ -0,0 +1,34 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewGenesisState constructs a GenesisState
+func NewGenesisState(memoRequiredAddresses []string) *GenesisState {
+	return &GenesisState{
+		MemoRequiredAddresses: memoRequiredAddresses,
+	}
+}
+
+// DefaultGenesisState gets raw genesis raw message for testing
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{}
+}
+
+// ValidateGenesis validates the provided memo genesis state
+func ValidateGenesis(data GenesisState) error {
+	seen := make(map[string]bool)
+	for _, address := range data.MemoRequiredAddresses {
+		if _, err := sdk.AccAddressFromBech32(address); err != nil {
+			return err
+		}
+		if seen[address] {
+			return fmt.Errorf("duplicate memo required address %s", address)
+		}
+		seen[address] = true
+	}
+	return nil
+}
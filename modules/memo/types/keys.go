@@ -0,0 +1,32 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// nolint
+const (
+	// module name
+	ModuleName = "memo"
+
+	// StoreKey is the default store key for memo
+	StoreKey = ModuleName
+
+	// RouterKey is the message route for memo
+	RouterKey = ModuleName
+
+	// QuerierRoute is the querier route for the memo store.
+	QuerierRoute = StoreKey
+
+	// Query endpoints supported by the memo querier
+	QueryMemoRequired = "memo_required"
+)
+
+var (
+	MemoRequiredKey = []byte{0x01} // memo required key
+)
+
+// GetMemoRequiredKey returns the memo required key bytes of the account
+func GetMemoRequiredKey(addr sdk.AccAddress) []byte {
+	return append(MemoRequiredKey, addr.Bytes()...)
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/address"
+)
+
+var testAddr, _ = sdk.AccAddressFromHex(crypto.AddressHash([]byte("test")).String())
+
+func init() {
+	address.ConfigureBech32Prefix()
+}
+
+func TestMsgSetMemoRequired(t *testing.T) {
+	msg := NewMsgSetMemoRequired(testAddr, true)
+	require.Equal(t, testAddr.String(), msg.Address)
+	require.True(t, msg.Required)
+	require.Equal(t, RouterKey, msg.Route())
+	require.Equal(t, TypeMsgSetMemoRequired, msg.Type())
+	require.Equal(t, []sdk.AccAddress{testAddr}, msg.GetSigners())
+	require.NoError(t, msg.ValidateBasic())
+
+	msg.Address = "invalid"
+	require.Error(t, msg.ValidateBasic())
+}
+
+func TestValidateGenesis(t *testing.T) {
+	require.NoError(t, ValidateGenesis(*DefaultGenesisState()))
+	require.NoError(t, ValidateGenesis(*NewGenesisState([]string{testAddr.String()})))
+	require.Error(t, ValidateGenesis(*NewGenesisState([]string{"invalid"})))
+	require.Error(t, ValidateGenesis(*NewGenesisState([]string{testAddr.String(), testAddr.String()})))
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/irisnet/irishub/modules/surcharge/types"
+)
+
+// GetQueryCmd returns the cli query commands for the surcharge module.
+func GetQueryCmd() *cobra.Command {
+	surchargeQueryCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Querying commands for the surcharge module",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+	surchargeQueryCmd.AddCommand(
+		GetCmdQuerySurcharges(),
+	)
+	return surchargeQueryCmd
+}
+
+// GetCmdQuerySurcharges implements a command to return the effective gas surcharges of all the msg types.
+func GetCmdQuerySurcharges() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "surcharges",
+		Short: "Query the extra gas charged for each msg type",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.Surcharges(context.Background(), &types.QuerySurchargesRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
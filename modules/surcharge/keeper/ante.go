@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ConsumeSurchargeDecorator consumes the extra gas of the msgs whose type has a
+// surcharge, so that the msgs storing large payloads pay for their state cost.
+// It should be called after ConsumeGasForTxSizeDecorator.
+type ConsumeSurchargeDecorator struct {
+	k Keeper
+}
+
+// NewConsumeSurchargeDecorator returns an instance of ConsumeSurchargeDecorator
+func NewConsumeSurchargeDecorator(k Keeper) ConsumeSurchargeDecorator {
+	return ConsumeSurchargeDecorator{
+		k: k,
+	}
+}
+
+// AnteHandle consumes the surcharge gas of each msg
+func (csd ConsumeSurchargeDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	surcharges := csd.k.GetSurcharges(ctx)
+	if len(surcharges) == 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		surcharge, ok := surcharges["/"+proto.MessageName(msg)]
+		if !ok {
+			continue
+		}
+
+		ctx.GasMeter().ConsumeGas(surcharge.GasPerMsg, "msg surcharge")
+		if m, ok := msg.(codec.ProtoMarshaler); ok {
+			ctx.GasMeter().ConsumeGas(mulGas(surcharge.GasPerByte, sdk.Gas(m.Size())), "msg surcharge per byte")
+		}
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// mulGas returns a*b, or the max gas if it overflows, which runs out of gas
+func mulGas(a, b sdk.Gas) sdk.Gas {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return lo
+}
@@ -0,0 +1,50 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/irisnet/irishub/modules/surcharge/keeper"
+	"github.com/irisnet/irishub/modules/surcharge/types"
+)
+
+func (suite *KeeperTestSuite) TestConsumeSurchargeDecorator() {
+	suite.keeper.SetParamSet(suite.ctx, types.NewParams([]types.Surcharge{
+		types.NewSurcharge("/cosmos.bank.v1beta1.MsgSend", 1000, 10),
+	}))
+
+	anteHandler := sdk.ChainAnteDecorators(keeper.NewConsumeSurchargeDecorator(suite.keeper))
+
+	addr := sdk.AccAddress([]byte("addr________________"))
+	send := banktypes.NewMsgSend(addr, addr, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1)))
+	multiSend := banktypes.NewMsgMultiSend(
+		[]banktypes.Input{banktypes.NewInput(addr, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1)))},
+		[]banktypes.Output{banktypes.NewOutput(addr, sdk.NewCoins(sdk.NewInt64Coin("uiris", 1)))},
+	)
+
+	tests := []struct {
+		name   string
+		msgs   []sdk.Msg
+		expGas uint64
+	}{
+		{"no surcharge", []sdk.Msg{multiSend}, 0},
+		{"one msg", []sdk.Msg{send}, 1000 + 10*uint64(send.Size())},
+		{"two surcharged msgs of three", []sdk.Msg{send, multiSend, send}, 2 * (1000 + 10*uint64(send.Size()))},
+	}
+
+	// the gas consumed by reading the params, which is the same for all the txs
+	var baseGas uint64
+	for i, tc := range tests {
+		txBuilder := suite.txConfig.NewTxBuilder()
+		suite.Require().NoError(txBuilder.SetMsgs(tc.msgs...))
+
+		ctx := suite.ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+		_, err := anteHandler(ctx, txBuilder.GetTx(), false)
+		suite.Require().NoError(err, tc.name)
+
+		if i == 0 {
+			baseGas = ctx.GasMeter().GasConsumed()
+		}
+		suite.Require().Equal(baseGas+tc.expGas, ctx.GasMeter().GasConsumed(), tc.name)
+	}
+}
@@ -0,0 +1,19 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/irisnet/irishub/modules/surcharge/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// Surcharges queries the effective surcharges of all the msg types
+func (k Keeper) Surcharges(c context.Context, _ *types.QuerySurchargesRequest) (*types.QuerySurchargesResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+	params := k.GetParamSet(ctx)
+
+	return &types.QuerySurchargesResponse{Surcharges: params.Surcharges}, nil
+}
@@ -0,0 +1,52 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/irisnet/irishub/modules/surcharge/types"
+)
+
+// Keeper of the surcharge store
+type Keeper struct {
+	paramSpace paramtypes.Subspace
+}
+
+// NewKeeper returns a surcharge keeper
+func NewKeeper(paramSpace paramtypes.Subspace) Keeper {
+	return Keeper{
+		paramSpace: paramSpace.WithKeyTable(types.ParamKeyTable()),
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("%s", types.ModuleName))
+}
+
+// GetParamSet returns surcharge params from the global param store.
+// The params may be absent on a chain upgraded without the surcharge genesis,
+// in which case no surcharge is applied.
+func (k Keeper) GetParamSet(ctx sdk.Context) types.Params {
+	var params types.Params
+	k.paramSpace.GetIfExists(ctx, types.KeySurcharges, &params.Surcharges)
+	return params
+}
+
+// SetParamSet set surcharge params to the global param store
+func (k Keeper) SetParamSet(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// GetSurcharges returns the surcharges keyed by the msg type url
+func (k Keeper) GetSurcharges(ctx sdk.Context) map[string]types.Surcharge {
+	surcharges := make(map[string]types.Surcharge)
+	for _, s := range k.GetParamSet(ctx).Surcharges {
+		surcharges[s.MsgTypeUrl] = s
+	}
+	return surcharges
+}
@@ -0,0 +1,84 @@
+package keeper_test
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/tendermint/tendermint/libs/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/irisnet/irishub/modules/surcharge/keeper"
+	"github.com/irisnet/irishub/modules/surcharge/types"
+	"github.com/irisnet/irishub/simapp"
+)
+
+type KeeperTestSuite struct {
+	suite.Suite
+
+	ctx      sdk.Context
+	keeper   keeper.Keeper
+	txConfig client.TxConfig
+}
+
+func (suite *KeeperTestSuite) SetupTest() {
+	encCfg := simapp.MakeTestEncodingConfig()
+
+	key := sdk.NewKVStoreKey(paramstypes.StoreKey)
+	tkey := sdk.NewTransientStoreKey(paramstypes.TStoreKey)
+
+	ms := store.NewCommitMultiStore(dbm.NewMemDB())
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	ms.MountStoreWithDB(tkey, sdk.StoreTypeTransient, nil)
+	suite.Require().NoError(ms.LoadLatestVersion())
+
+	paramsKeeper := paramskeeper.NewKeeper(encCfg.Marshaler, encCfg.Amino, key, tkey)
+
+	suite.ctx = sdk.NewContext(ms, tmproto.Header{Height: 1}, false, log.NewNopLogger())
+	suite.keeper = keeper.NewKeeper(paramsKeeper.Subspace(types.ModuleName))
+	suite.txConfig = encCfg.TxConfig
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+	suite.Run(t, new(KeeperTestSuite))
+}
+
+func (suite *KeeperTestSuite) TestGetParamSetIfNotExist() {
+	suite.Require().Empty(suite.keeper.GetParamSet(suite.ctx).Surcharges)
+	suite.Require().Empty(suite.keeper.GetSurcharges(suite.ctx))
+}
+
+func (suite *KeeperTestSuite) TestSetGetParamSet() {
+	params := types.DefaultParams()
+	suite.keeper.SetParamSet(suite.ctx, params)
+
+	suite.Require().Equal(params, suite.keeper.GetParamSet(suite.ctx))
+
+	surcharges := suite.keeper.GetSurcharges(suite.ctx)
+	suite.Require().Len(surcharges, len(params.Surcharges))
+	for _, s := range params.Surcharges {
+		suite.Require().Equal(s, surcharges[s.MsgTypeUrl])
+	}
+}
+
+func (suite *KeeperTestSuite) TestGRPCQuerySurcharges() {
+	params := types.DefaultParams()
+	suite.keeper.SetParamSet(suite.ctx, params)
+
+	queryHelper := baseapp.NewQueryServerTestHelper(suite.ctx, simapp.MakeTestEncodingConfig().InterfaceRegistry)
+	types.RegisterQueryServer(queryHelper, suite.keeper)
+	queryClient := types.NewQueryClient(queryHelper)
+
+	resp, err := queryClient.Surcharges(gocontext.Background(), &types.QuerySurchargesRequest{})
+	suite.NoError(err)
+	suite.Equal(params.Surcharges, resp.Surcharges)
+}
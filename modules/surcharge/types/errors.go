@@ -0,0 +1,11 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// surcharge module sentinel errors
+var (
+	ErrInvalidSurcharge   = sdkerrors.Register(ModuleName, 2, "invalid surcharge")
+	ErrDuplicateSurcharge = sdkerrors.Register(ModuleName, 3, "duplicate surcharge")
+)
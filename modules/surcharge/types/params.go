@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+// default paramspace for params keeper
+const (
+	DefaultParamSpace = ModuleName
+
+	// DefaultGasPerByte is the default extra gas charged for each byte of the msgs storing large payloads
+	DefaultGasPerByte uint64 = 20
+
+	// MaxGasPerByte is the max extra gas charged for each byte of a msg
+	MaxGasPerByte uint64 = 10000
+)
+
+// Parameter store key
+var (
+	// params store for surcharges
+	KeySurcharges = []byte("Surcharges")
+)
+
+// ParamKeyTable for surcharge module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// NewSurcharge constructs a new Surcharge instance
+func NewSurcharge(msgTypeURL string, gasPerMsg, gasPerByte uint64) Surcharge {
+	return Surcharge{
+		MsgTypeUrl: msgTypeURL,
+		GasPerMsg:  gasPerMsg,
+		GasPerByte: gasPerByte,
+	}
+}
+
+// Validate returns err if the Surcharge is invalid
+func (s Surcharge) Validate() error {
+	if !strings.HasPrefix(s.MsgTypeUrl, "/") || len(strings.TrimSpace(s.MsgTypeUrl)) <= 1 {
+		return sdkerrors.Wrapf(ErrInvalidSurcharge, "msg type url [%s] must start with '/'", s.MsgTypeUrl)
+	}
+	if s.GasPerByte > MaxGasPerByte {
+		return sdkerrors.Wrapf(ErrInvalidSurcharge, "gas per byte [%d] of [%s] must not exceed %d", s.GasPerByte, s.MsgTypeUrl, MaxGasPerByte)
+	}
+	return nil
+}
+
+// NewParams constructs a new Params instance
+func NewParams(surcharges []Surcharge) Params {
+	return Params{
+		Surcharges: surcharges,
+	}
+}
+
+// DefaultParams returns default surcharge module parameters
+func DefaultParams() Params {
+	return Params{
+		Surcharges: []Surcharge{
+			NewSurcharge("/irismod.nft.MsgMintNFT", 0, DefaultGasPerByte),
+			NewSurcharge("/irismod.service.MsgDefineService", 0, DefaultGasPerByte),
+			NewSurcharge("/irismod.record.MsgCreateRecord", 0, DefaultGasPerByte),
+		},
+	}
+}
+
+// String implements the Stringer interface.
+func (p Params) String() string {
+	out, _ := yaml.Marshal(p)
+	return string(out)
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeySurcharges, &p.Surcharges, validateSurcharges),
+	}
+}
+
+// Validate returns err if the Params is invalid
+func (p Params) Validate() error {
+	return validateSurcharges(p.Surcharges)
+}
+
+func validateSurcharges(i interface{}) error {
+	v, ok := i.([]Surcharge)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range v {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if seen[s.MsgTypeUrl] {
+			return sdkerrors.Wrapf(ErrDuplicateSurcharge, "msg type url [%s] is duplicated", s.MsgTypeUrl)
+		}
+		seen[s.MsgTypeUrl] = true
+	}
+
+	return nil
+}
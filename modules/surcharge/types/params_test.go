@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		expectPass bool
+		params     Params
+	}{
+		{true, DefaultParams()},
+		{true, NewParams(nil)},
+		{false, NewParams([]Surcharge{NewSurcharge("irismod.nft.MsgMintNFT", 0, 10)})},
+		{false, NewParams([]Surcharge{NewSurcharge("/", 0, 10)})},
+		{true, NewParams([]Surcharge{NewSurcharge("/irismod.nft.MsgMintNFT", 0, MaxGasPerByte)})},
+		{false, NewParams([]Surcharge{NewSurcharge("/irismod.nft.MsgMintNFT", 0, MaxGasPerByte+1)})},
+		{false, NewParams([]Surcharge{NewSurcharge("/irismod.nft.MsgMintNFT", 0, 10), NewSurcharge("/irismod.nft.MsgMintNFT", 10, 0)})},
+	}
+	for i, tc := range tests {
+		err := tc.params.Validate()
+		if tc.expectPass {
+			require.NoError(t, err, "%d: %+v", i, err)
+		} else {
+			require.Error(t, err, "%d: %+v", i, err)
+		}
+	}
+}
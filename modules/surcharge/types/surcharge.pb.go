@@ -0,0 +1,579 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: surcharge/surcharge.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Surcharge defines the extra gas charged for a msg type
+type Surcharge struct {
+	// type url of the msg, e.g. /irismod.nft.MsgMintNFT
+	MsgTypeUrl string `protobuf:"bytes,1,opt,name=msg_type_url,json=msgTypeUrl,proto3" json:"msg_type_url,omitempty" yaml:"msg_type_url"`
+	// extra gas charged for each msg
+	GasPerMsg uint64 `protobuf:"varint,2,opt,name=gas_per_msg,json=gasPerMsg,proto3" json:"gas_per_msg,omitempty" yaml:"gas_per_msg"`
+	// extra gas charged for each byte of the msg
+	GasPerByte uint64 `protobuf:"varint,3,opt,name=gas_per_byte,json=gasPerByte,proto3" json:"gas_per_byte,omitempty" yaml:"gas_per_byte"`
+}
+
+func (m *Surcharge) Reset()         { *m = Surcharge{} }
+func (m *Surcharge) String() string { return proto.CompactTextString(m) }
+func (*Surcharge) ProtoMessage()    {}
+func (*Surcharge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0f53e3423379f8a1, []int{0}
+}
+func (m *Surcharge) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Surcharge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Surcharge.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Surcharge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Surcharge.Merge(m, src)
+}
+func (m *Surcharge) XXX_Size() int {
+	return m.Size()
+}
+func (m *Surcharge) XXX_DiscardUnknown() {
+	xxx_messageInfo_Surcharge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Surcharge proto.InternalMessageInfo
+
+func (m *Surcharge) GetMsgTypeUrl() string {
+	if m != nil {
+		return m.MsgTypeUrl
+	}
+	return ""
+}
+
+func (m *Surcharge) GetGasPerMsg() uint64 {
+	if m != nil {
+		return m.GasPerMsg
+	}
+	return 0
+}
+
+func (m *Surcharge) GetGasPerByte() uint64 {
+	if m != nil {
+		return m.GasPerByte
+	}
+	return 0
+}
+
+// Params defines surcharge module's parameters
+type Params struct {
+	Surcharges []Surcharge `protobuf:"bytes,1,rep,name=surcharges,proto3" json:"surcharges"`
+}
+
+func (m *Params) Reset()      { *m = Params{} }
+func (*Params) ProtoMessage() {}
+func (*Params) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0f53e3423379f8a1, []int{1}
+}
+func (m *Params) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params.Merge(m, src)
+}
+func (m *Params) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params proto.InternalMessageInfo
+
+func (m *Params) GetSurcharges() []Surcharge {
+	if m != nil {
+		return m.Surcharges
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Surcharge)(nil), "irishub.surcharge.Surcharge")
+	proto.RegisterType((*Params)(nil), "irishub.surcharge.Params")
+}
+
+func init() { proto.RegisterFile("surcharge/surcharge.proto", fileDescriptor_0f53e3423379f8a1) }
+
+var fileDescriptor_0f53e3423379f8a1 = []byte{
+	// 303 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xc1, 0x4a, 0xf3, 0x40,
+	0x14, 0x85, 0x33, 0x7f, 0x4b, 0xa1, 0xd3, 0x7f, 0x63, 0x14, 0x8d, 0x22, 0x93, 0x92, 0x55, 0x57,
+	0x19, 0xa8, 0x20, 0xd8, 0xe5, 0xac, 0x15, 0x4a, 0xd4, 0x8d, 0x9b, 0x30, 0xad, 0xc3, 0x34, 0x90,
+	0x71, 0xc2, 0xdc, 0x64, 0x91, 0xb7, 0x70, 0xe9, 0xd2, 0xd7, 0xf0, 0x0d, 0xba, 0xec, 0xd2, 0x55,
+	0x90, 0xe4, 0x0d, 0xfa, 0x04, 0x92, 0xc4, 0xc6, 0x80, 0xbb, 0x3b, 0x7c, 0xf7, 0xdc, 0xc3, 0x39,
+	0x83, 0xcf, 0x21, 0x33, 0xeb, 0x0d, 0x37, 0x52, 0xd0, 0x6e, 0xf2, 0x13, 0xa3, 0x53, 0x6d, 0x1f,
+	0x45, 0x26, 0x82, 0x4d, 0xb6, 0xf2, 0x3b, 0x70, 0x71, 0x22, 0xb5, 0xd4, 0x0d, 0xa5, 0xf5, 0xd4,
+	0x2e, 0x7a, 0x1f, 0x08, 0x8f, 0xef, 0x0f, 0x3b, 0xf6, 0x0d, 0xfe, 0xaf, 0x40, 0x86, 0x69, 0x9e,
+	0x88, 0x30, 0x33, 0xb1, 0x83, 0xa6, 0x68, 0x36, 0x66, 0x67, 0xfb, 0xc2, 0x3d, 0xce, 0xb9, 0x8a,
+	0x17, 0x5e, 0x9f, 0x7a, 0x01, 0x56, 0x20, 0x1f, 0xf2, 0x44, 0x3c, 0x9a, 0xd8, 0xbe, 0xc6, 0x13,
+	0xc9, 0x21, 0x4c, 0x84, 0x09, 0x15, 0x48, 0xe7, 0xdf, 0x14, 0xcd, 0x86, 0xec, 0x74, 0x5f, 0xb8,
+	0x76, 0xab, 0xec, 0x41, 0x2f, 0x18, 0x4b, 0x0e, 0x4b, 0x61, 0xee, 0x40, 0xd6, 0x96, 0x07, 0xb4,
+	0xca, 0x53, 0xe1, 0x0c, 0x1a, 0x61, 0xcf, 0xb2, 0x4f, 0xbd, 0x00, 0xb7, 0x4a, 0xd6, 0x3e, 0x46,
+	0x4b, 0x6e, 0xb8, 0x02, 0x9b, 0x61, 0xdc, 0x05, 0x05, 0x07, 0x4d, 0x07, 0xb3, 0xc9, 0xfc, 0xd2,
+	0xff, 0xd3, 0x81, 0xdf, 0x25, 0x65, 0xc3, 0x6d, 0xe1, 0x5a, 0x41, 0x4f, 0xb5, 0x18, 0xbe, 0xbd,
+	0xbb, 0x16, 0xbb, 0xdd, 0x96, 0x04, 0xed, 0x4a, 0x82, 0xbe, 0x4a, 0x82, 0x5e, 0x2b, 0x62, 0xed,
+	0x2a, 0x62, 0x7d, 0x56, 0xc4, 0x7a, 0x9a, 0xcb, 0x28, 0xad, 0xaf, 0xad, 0xb5, 0xa2, 0xf5, 0xe5,
+	0x17, 0x91, 0xd2, 0x1f, 0x07, 0xaa, 0xf4, 0x73, 0x16, 0x0b, 0xf8, 0xfd, 0x06, 0x5a, 0x97, 0x04,
+	0xab, 0x51, 0x53, 0xf2, 0xd5, 0xf7, 0x00, 0xea, 0x6b, 0x9e, 0xff, 0xaa, 0x01, 0x00, 0x00,
+}
+
+func (m *Surcharge) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Surcharge) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Surcharge) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.GasPerByte != 0 {
+		i = encodeVarintSurcharge(dAtA, i, uint64(m.GasPerByte))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.GasPerMsg != 0 {
+		i = encodeVarintSurcharge(dAtA, i, uint64(m.GasPerMsg))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.MsgTypeUrl) > 0 {
+		i -= len(m.MsgTypeUrl)
+		copy(dAtA[i:], m.MsgTypeUrl)
+		i = encodeVarintSurcharge(dAtA, i, uint64(len(m.MsgTypeUrl)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Params) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Params) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Surcharges) > 0 {
+		for iNdEx := len(m.Surcharges) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Surcharges[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSurcharge(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintSurcharge(dAtA []byte, offset int, v uint64) int {
+	offset -= sovSurcharge(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Surcharge) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MsgTypeUrl)
+	if l > 0 {
+		n += 1 + l + sovSurcharge(uint64(l))
+	}
+	if m.GasPerMsg != 0 {
+		n += 1 + sovSurcharge(uint64(m.GasPerMsg))
+	}
+	if m.GasPerByte != 0 {
+		n += 1 + sovSurcharge(uint64(m.GasPerByte))
+	}
+	return n
+}
+
+func (m *Params) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Surcharges) > 0 {
+		for _, e := range m.Surcharges {
+			l = e.Size()
+			n += 1 + l + sovSurcharge(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovSurcharge(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozSurcharge(x uint64) (n int) {
+	return sovSurcharge(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Surcharge) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSurcharge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Surcharge: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Surcharge: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MsgTypeUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSurcharge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSurcharge
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSurcharge
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MsgTypeUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasPerMsg", wireType)
+			}
+			m.GasPerMsg = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSurcharge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GasPerMsg |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasPerByte", wireType)
+			}
+			m.GasPerByte = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSurcharge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GasPerByte |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSurcharge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSurcharge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSurcharge
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Params: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Params: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Surcharges", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSurcharge
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSurcharge
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSurcharge
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Surcharges = append(m.Surcharges, Surcharge{})
+			if err := m.Surcharges[len(m.Surcharges)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSurcharge(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSurcharge
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipSurcharge(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowSurcharge
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSurcharge
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSurcharge
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthSurcharge
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupSurcharge
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthSurcharge
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthSurcharge        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowSurcharge          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupSurcharge = fmt.Errorf("proto: unexpected end of group")
+)